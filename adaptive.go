@@ -0,0 +1,106 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// AdaptiveWindow applies agg to the window of column which length varies bar by bar.
+// Periods holds the window length for every bar of the column, e.g. dominant cycle period or volatility-scaled lookback,
+// and must be of the same length. Periods are rounded to the nearest integer and can't be less than 1.
+// The value is NaN when the period is NaN or there are not enough bars for the window.
+func AdaptiveWindow(column, periods series.Data, agg series.AggregateFunc) (result series.Data) {
+	return adaptive(column, periods, func(begin, end int) DType {
+		return agg(column.Slice(begin, end))
+	})
+}
+
+// AdaptiveRSI is the relative strength index with the lookback of periods.
+// Gains and losses are simple sums over the window of price changes, like in Cutler's RSI,
+// because exponential smoothing has no fixed window to adapt.
+func AdaptiveRSI(column, periods series.Data) (rsi series.Data) {
+	values := column.Values()
+
+	rsi = adaptive(column, periods, func(begin, end int) DType {
+		// Window of n changes spans n+1 prices.
+		if begin == 0 {
+			return math.NaN()
+		}
+
+		var gain, loss DType
+
+		for i := begin; i < end; i++ {
+			change := values[i] - values[i-1]
+			if change > 0 {
+				gain += change
+			} else {
+				loss -= change
+			}
+		}
+
+		return 100 * divide(gain, gain+loss)
+	})
+
+	return rsi
+}
+
+// AdaptiveSTOCH is the stochastic oscillator %K with the lookback of periods.
+// Values are in range 0-1 like of STOCH without options.
+func AdaptiveSTOCH(high, low, close, periods series.Data) (stoch series.Data) {
+	c := close.Values()
+
+	stoch = adaptive(close, periods, func(begin, end int) DType {
+		var (
+			highest = series.Max(high.Slice(begin, end))
+			lowest  = series.Min(low.Slice(begin, end))
+		)
+
+		return divide(c[end-1]-lowest, highest-lowest)
+	})
+
+	return stoch
+}
+
+// AdaptiveCCI is the commodity channel index with the lookback of periods.
+// It's the deviation of typical price from its mean over the window divided by 0.015 of the mean absolute deviation.
+func AdaptiveCCI(high, low, close, periods series.Data) (cci series.Data) {
+	tp := high.Clone().Add(low).Add(close).DivScalar(3)
+	values := tp.Values()
+
+	cci = adaptive(close, periods, func(begin, end int) DType {
+		mean, dev := meanDeviation(values[begin:end])
+		return divide(values[end-1]-mean, 0.015*dev)
+	})
+
+	return cci
+}
+
+// adaptive calls fn with bounds of the window of every bar, the length of the window is taken from periods.
+func adaptive(column, periods series.Data, fn func(begin, end int) DType) series.Data {
+	var (
+		result = column.Clone()
+		dst    = result.Values()
+		p      = periods.Values()
+	)
+
+	for i := range dst {
+		if series.IsNA(p[i]) {
+			dst[i] = math.NaN()
+			continue
+		}
+
+		period := int(math.Round(p[i]))
+		if period < 1 {
+			period = 1
+		}
+
+		if period > i+1 {
+			dst[i] = math.NaN()
+			continue
+		}
+
+		dst[i] = fn(i+1-period, i+1)
+	}
+
+	return result
+}
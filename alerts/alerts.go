@@ -0,0 +1,287 @@
+// Package alerts evaluates conditions over streaming indicator values on bar close and notifies handlers
+// when they trigger.
+//
+// Engine.OnBar has the signature of stream.Consumer's OnBar, so the engine plugs directly into the live feed:
+//
+//	engine := alerts.NewEngine()
+//	engine.Track("rsi", stream.NewRSI(14))
+//	engine.Track("sma", stream.NewSMA(50))
+//	engine.Handle(alerts.Webhook("https://example.com/hook", nil))
+//
+//	if err := engine.AddExpr("oversold", "rsi < 30 and close crosses above sma"); err != nil {
+//		return err
+//	}
+//
+//	consumer := stream.Consumer{Source: src, Decoder: dec, OnBar: engine.OnBar}
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+)
+
+// Values are indicator values of a bar by names.
+// Besides tracked indicators they always hold "open", "high", "low", "close" and "volume" of the bar.
+type Values map[string]fta.DType
+
+// Updater is the streaming indicator, e.g. stream.SMA, stream.EMA or stream.RSI.
+type Updater interface {
+	Update(v fta.DType) fta.DType
+}
+
+// Condition decides whether the alert triggers by values of the current and the previous bars.
+// Prev is nil at the first bar.
+type Condition interface {
+	Check(cur, prev Values) bool
+}
+
+// Predicate is the adapter of Go functions to Condition interface.
+type Predicate func(cur, prev Values) bool
+
+// Check implements Condition interface.
+func (f Predicate) Check(cur, prev Values) bool {
+	return f(cur, prev)
+}
+
+// Alert is the named condition.
+type Alert struct {
+	Name      string
+	Condition Condition
+	// EveryBar triggers the alert on every bar while the condition holds.
+	// By default the alert triggers only when the condition becomes true.
+	EveryBar bool
+}
+
+// Event is the triggered alert.
+type Event struct {
+	Alert  string
+	Bar    fta.Bar
+	Values Values
+}
+
+// MarshalJSON implements json.Marshaler interface, it's the payload of webhooks.
+// Time is encoded in RFC 3339 format in UTC, NaN values are encoded as null.
+func (e Event) MarshalJSON() ([]byte, error) {
+	number := func(v fta.DType) *float64 {
+		if series.IsNA(v) {
+			return nil
+		}
+		f := float64(v)
+		return &f
+	}
+
+	values := make(map[string]*float64, len(e.Values))
+	for name, v := range e.Values {
+		values[name] = number(v)
+	}
+
+	return json.Marshal(struct {
+		Alert  string              `json:"alert"`
+		Time   string              `json:"time"`
+		Values map[string]*float64 `json:"values"`
+	}{
+		Alert:  e.Alert,
+		Time:   time.Unix(0, e.Bar.Time).UTC().Format(time.RFC3339Nano),
+		Values: values,
+	})
+}
+
+// Handler receives triggered alerts.
+type Handler func(e Event) error
+
+// Chan returns the handler sending events to the channel. It blocks until the event is received.
+func Chan(ch chan<- Event) Handler {
+	return func(e Event) error {
+		ch <- e
+		return nil
+	}
+}
+
+var builtins = []string{"open", "high", "low", "close", "volume"}
+
+type input struct {
+	name string
+	fn   func(bar fta.Bar) fta.DType
+}
+
+type state struct {
+	Alert
+	active bool
+}
+
+// Engine updates tracked indicators by closed bars and checks alerts.
+// It's safe for concurrent use.
+type Engine struct {
+	mu       sync.Mutex
+	inputs   []input
+	alerts   []*state
+	handlers []Handler
+	prev     Values
+}
+
+// NewEngine returns the engine without indicators and alerts.
+func NewEngine() *Engine {
+	return &Engine{}
+}
+
+// Track adds the indicator updated by close prices under the name.
+func (e *Engine) Track(name string, indicator Updater) {
+	e.TrackFunc(name, func(bar fta.Bar) fta.DType {
+		return indicator.Update(bar.Close)
+	})
+}
+
+// TrackFunc adds the value computed by fn from every bar under the name.
+func (e *Engine) TrackFunc(name string, fn func(bar fta.Bar) fta.DType) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.inputs = append(e.inputs, input{name: name, fn: fn})
+}
+
+// Handle adds the handler of triggered alerts.
+func (e *Engine) Handle(h Handler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.handlers = append(e.handlers, h)
+}
+
+// Add registers the alert. Names of alerts must be unique.
+// Expression conditions may use only tracked and builtin values.
+func (e *Engine) Add(alert Alert) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if alert.Condition == nil {
+		return fmt.Errorf("alerts: %q: condition is required", alert.Name)
+	}
+
+	for _, s := range e.alerts {
+		if s.Name == alert.Name {
+			return fmt.Errorf("alerts: %q is already registered", alert.Name)
+		}
+	}
+
+	if expr, ok := alert.Condition.(*Expr); ok {
+		for _, name := range expr.Vars() {
+			if !e.known(name) {
+				return fmt.Errorf("alerts: %q: unknown value %q", alert.Name, name)
+			}
+		}
+	}
+
+	e.alerts = append(e.alerts, &state{Alert: alert})
+
+	return nil
+}
+
+// AddExpr parses the expression and registers it as the alert triggered when the expression becomes true.
+func (e *Engine) AddExpr(name, src string) error {
+	expr, err := Parse(src)
+	if err != nil {
+		return err
+	}
+	return e.Add(Alert{Name: name, Condition: expr})
+}
+
+// Remove unregisters the alert by name.
+func (e *Engine) Remove(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i, s := range e.alerts {
+		if s.Name == name {
+			e.alerts = append(e.alerts[:i], e.alerts[i+1:]...)
+			return
+		}
+	}
+}
+
+// Alerts returns names of registered alerts in sorted order.
+func (e *Engine) Alerts() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	names := make([]string, len(e.alerts))
+	for i, s := range e.alerts {
+		names[i] = s.Name
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// OnBar updates indicators by the closed bar, checks alerts and passes triggered ones to handlers.
+// Handlers are called after the state is updated, so they may use the engine.
+// All handlers receive every event, the first error of handlers is returned.
+func (e *Engine) OnBar(bar fta.Bar) error {
+	events := e.update(bar)
+
+	e.mu.Lock()
+	handlers := append([]Handler(nil), e.handlers...)
+	e.mu.Unlock()
+
+	var err error
+
+	for _, event := range events {
+		for _, h := range handlers {
+			if herr := h(event); herr != nil && err == nil {
+				err = fmt.Errorf("alerts: %s: %w", event.Alert, herr)
+			}
+		}
+	}
+
+	return err
+}
+
+func (e *Engine) update(bar fta.Bar) (events []Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	cur := Values{
+		"open":   bar.Open,
+		"high":   bar.High,
+		"low":    bar.Low,
+		"close":  bar.Close,
+		"volume": bar.Volume,
+	}
+
+	for _, in := range e.inputs {
+		cur[in.name] = in.fn(bar)
+	}
+
+	for _, s := range e.alerts {
+		ok := s.Condition.Check(cur, e.prev)
+
+		if ok && (!s.active || s.EveryBar) {
+			events = append(events, Event{Alert: s.Name, Bar: bar, Values: cur})
+		}
+
+		s.active = ok
+	}
+
+	e.prev = cur
+
+	return events
+}
+
+func (e *Engine) known(name string) bool {
+	for _, b := range builtins {
+		if b == name {
+			return true
+		}
+	}
+	for _, in := range e.inputs {
+		if in.name == name {
+			return true
+		}
+	}
+	return false
+}
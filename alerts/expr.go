@@ -0,0 +1,497 @@
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// ErrSyntax is returned when the expression can't be parsed.
+var ErrSyntax = errors.New("alerts: syntax error")
+
+// Expr is the compiled condition of the expression language over named values, e.g.
+//
+//	rsi < 30 and close > sma200
+//	close crosses above upper or close crosses below lower
+//	abs(close - prev(close)) / prev(close) > 0.05
+//
+// Operators by increasing precedence are: "or" ("||"), "and" ("&&"), "not" ("!"),
+// comparisons "<", "<=", ">", ">=", "==", "!=", "crosses above", "crosses below",
+// "+", "-" and "*", "/". Functions are abs(x), min(x, y), max(x, y) and prev(x), the value of x at the previous bar.
+// Comparisons and logical operators give 1 for true and 0 for false, NaN values never satisfy comparisons.
+type Expr struct {
+	src  string
+	root node
+	vars []string
+}
+
+// Parse compiles the expression.
+func Parse(src string) (*Expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{src: src, tokens: tokens, vars: map[string]bool{}}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+
+	expr := &Expr{src: src, root: root}
+	for name := range p.vars {
+		expr.vars = append(expr.vars, name)
+	}
+
+	return expr, nil
+}
+
+// MustParse is like Parse but panics if the expression can't be parsed.
+func MustParse(src string) *Expr {
+	expr, err := Parse(src)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// String returns source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Vars returns names of values used by the expression in no particular order.
+func (e *Expr) Vars() []string {
+	return e.vars
+}
+
+// Eval evaluates the expression by values of the current and the previous bars, prev may be nil.
+// Missing values are NaN.
+func (e *Expr) Eval(cur, prev Values) fta.DType {
+	return e.root.eval(cur, prev)
+}
+
+// Check implements Condition interface, the expression holds when it evaluates to non-zero value.
+func (e *Expr) Check(cur, prev Values) bool {
+	return truth(e.Eval(cur, prev))
+}
+
+type node interface {
+	eval(cur, prev Values) fta.DType
+}
+
+type (
+	numNode  fta.DType
+	varNode  string
+	negNode  struct{ x node }
+	notNode  struct{ x node }
+	prevNode struct{ x node }
+	binNode  struct {
+		op   string
+		x, y node
+	}
+	crossNode struct {
+		x, y  node
+		above bool
+	}
+)
+
+func (n numNode) eval(cur, prev Values) fta.DType { return fta.DType(n) }
+
+func (n varNode) eval(cur, prev Values) fta.DType {
+	v, ok := cur[string(n)]
+	if !ok {
+		return math.NaN()
+	}
+	return v
+}
+
+func (n negNode) eval(cur, prev Values) fta.DType { return -n.x.eval(cur, prev) }
+
+func (n notNode) eval(cur, prev Values) fta.DType { return boolean(!truth(n.x.eval(cur, prev))) }
+
+func (n prevNode) eval(cur, prev Values) fta.DType { return n.x.eval(prev, nil) }
+
+func (n binNode) eval(cur, prev Values) fta.DType {
+	x := n.x.eval(cur, prev)
+
+	// Short-circuit logical operators.
+	switch n.op {
+	case "and":
+		return boolean(truth(x) && truth(n.y.eval(cur, prev)))
+	case "or":
+		return boolean(truth(x) || truth(n.y.eval(cur, prev)))
+	}
+
+	y := n.y.eval(cur, prev)
+
+	switch n.op {
+	case "+":
+		return x + y
+	case "-":
+		return x - y
+	case "*":
+		return x * y
+	case "/":
+		return x / y
+	case "<":
+		return boolean(x < y)
+	case "<=":
+		return boolean(x <= y)
+	case ">":
+		return boolean(x > y)
+	case ">=":
+		return boolean(x >= y)
+	case "==":
+		return boolean(x == y)
+	case "!=":
+		return boolean(!series.IsNA(x) && !series.IsNA(y) && x != y)
+	case "min":
+		return math.Min(x, y)
+	case "max":
+		return math.Max(x, y)
+	}
+
+	panic("alerts: unknown operator " + n.op)
+}
+
+func (n crossNode) eval(cur, prev Values) fta.DType {
+	var (
+		x0 = n.x.eval(prev, nil)
+		y0 = n.y.eval(prev, nil)
+		x1 = n.x.eval(cur, prev)
+		y1 = n.y.eval(cur, prev)
+	)
+
+	if n.above {
+		return boolean(x0 <= y0 && x1 > y1)
+	}
+	return boolean(x0 >= y0 && x1 < y1)
+}
+
+type absNode struct{ x node }
+
+func (n absNode) eval(cur, prev Values) fta.DType { return math.Abs(n.x.eval(cur, prev)) }
+
+func truth(v fta.DType) bool {
+	return !series.IsNA(v) && v != 0
+}
+
+func boolean(b bool) fta.DType {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNum
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func lex(src string) (tokens []token, err error) {
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case unicode.IsDigit(c) || c == '.':
+			j := i
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.' || src[j] == 'e' || src[j] == 'E' ||
+				(j > i && (src[j] == '-' || src[j] == '+') && (src[j-1] == 'e' || src[j-1] == 'E'))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokNum, text: src[i:j], pos: i})
+			i = j
+
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(src) && (unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j])) || src[j] == '_' || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[i:j], pos: i})
+			i = j
+
+		default:
+			op := ""
+			for _, candidate := range []string{"<=", ">=", "==", "!=", "&&", "||", "<", ">", "!", "+", "-", "*", "/", "(", ")", ","} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("%w: %q: unexpected %q at %d", ErrSyntax, src, c, i)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, text: "end of expression", pos: len(src)}), nil
+}
+
+type parser struct {
+	src    string
+	tokens []token
+	pos    int
+	vars   map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it's one of operators or keywords.
+func (p *parser) accept(texts ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokOp && tok.kind != tokIdent {
+		return "", false
+	}
+	for _, text := range texts {
+		if tok.text == text {
+			p.next()
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		tok := p.peek()
+		return p.errorf(tok, "expected %q, got %q", text, tok.text)
+	}
+	return nil
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %q: %s at %d", ErrSyntax, p.src, fmt.Sprintf(format, args...), tok.pos)
+}
+
+func (p *parser) parseOr() (node, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("or", "||"); !ok {
+			return x, nil
+		}
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = binNode{op: "or", x: x, y: y}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("and", "&&"); !ok {
+			return x, nil
+		}
+		y, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		x = binNode{op: "and", x: x, y: y}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, ok := p.accept("not", "!"); ok {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x: x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	x, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.accept("crosses"); ok {
+		dir, ok := p.accept("above", "below")
+		if !ok {
+			tok := p.peek()
+			return nil, p.errorf(tok, "expected \"above\" or \"below\", got %q", tok.text)
+		}
+		y, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return crossNode{x: x, y: y, above: dir == "above"}, nil
+	}
+
+	if op, ok := p.accept("<=", ">=", "==", "!=", "<", ">"); ok {
+		y, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return binNode{op: op, x: x, y: y}, nil
+	}
+
+	return x, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	x, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		x = binNode{op: op, x: x, y: y}
+	}
+}
+
+func (p *parser) parseProduct() (node, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = binNode{op: op, x: x, y: y}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.accept("-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+var keywords = map[string]bool{"and": true, "or": true, "not": true, "crosses": true, "above": true, "below": true}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+
+	switch {
+	case tok.kind == tokNum:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "invalid number %q", tok.text)
+		}
+		return numNode(v), nil
+
+	case tok.kind == tokOp && tok.text == "(":
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return x, nil
+
+	case tok.kind == tokIdent && !keywords[tok.text]:
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(tok)
+		}
+		p.vars[tok.text] = true
+		return varNode(tok.text), nil
+	}
+
+	return nil, p.errorf(tok, "unexpected %q", tok.text)
+}
+
+func (p *parser) parseCall(fn token) (node, error) {
+	var args []node
+
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	arity := map[string]int{"abs": 1, "prev": 1, "min": 2, "max": 2}
+
+	n, ok := arity[fn.text]
+	if !ok {
+		return nil, p.errorf(fn, "unknown function %q", fn.text)
+	}
+	if len(args) != n {
+		return nil, p.errorf(fn, "%s takes %d arguments, got %d", fn.text, n, len(args))
+	}
+
+	switch fn.text {
+	case "abs":
+		return absNode{x: args[0]}, nil
+	case "prev":
+		return prevNode{x: args[0]}, nil
+	default:
+		return binNode{op: fn.text, x: args[0], y: args[1]}, nil
+	}
+}
@@ -0,0 +1,153 @@
+package alerts
+
+import (
+	"errors"
+	stdmath "math"
+	"sort"
+	"testing"
+
+	"github.com/WinPooh32/fta"
+)
+
+func TestEval(t *testing.T) {
+	nan := fta.DType(stdmath.NaN())
+
+	var (
+		cur  = Values{"close": 105, "prev_close": 100, "sma": 102, "rsi": 25, "upper": 104, "nan": nan}
+		prev = Values{"close": 100, "sma": 101, "rsi": 35, "upper": 103}
+	)
+
+	tests := []struct {
+		src  string
+		want fta.DType
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"12 / 2 / 3", 2},
+		{"-2 * -3", 6},
+		{"1.5e2 + .5", 150.5},
+		{"rsi < 30 and close > sma", 1},
+		{"rsi < 30 && close < sma", 0},
+		{"rsi > 30 or close > sma", 1},
+		{"not rsi < 30", 0},
+		{"!(rsi < 30) || close == 105", 1},
+		{"rsi < 30 or close < sma and close > sma", 1},
+		{"close crosses above upper", 1},
+		{"close crosses below upper", 0},
+		{"sma crosses above close", 0},
+		{"abs(close - prev(close)) / prev(close)", 0.05},
+		{"min(close, sma) + max(rsi, 30)", 132},
+		{"prev(rsi) - rsi", 10},
+		{"close != sma", 1},
+		{"unknown", nan},
+		{"nan > 0 or nan <= 0", 0},
+		{"nan == nan", 0},
+		{"nan != 1", 0},
+		{"not nan", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := Parse(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, want := float64(expr.Eval(cur, prev)), float64(tt.want)
+			if stdmath.IsNaN(want) != stdmath.IsNaN(got) || stdmath.Abs(got-want) > 1e-6 {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestEvalFirstBar(t *testing.T) {
+	// Values of the missing previous bar are NaN, so crosses and prev never hold.
+	cur := Values{"close": 105, "upper": 104}
+
+	for _, src := range []string{"close crosses above upper", "prev(close) < close"} {
+		if MustParse(src).Check(cur, nil) {
+			t.Errorf("%s holds without the previous bar", src)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"close >",
+		"(close > 1",
+		"close > 1)",
+		"close crosses sma",
+		"close $ 1",
+		"abs(close, 1)",
+		"prev()",
+		"median(close)",
+		"close and",
+		"1..2 > 0",
+	}
+
+	for _, src := range tests {
+		if _, err := Parse(src); !errors.Is(err, ErrSyntax) {
+			t.Errorf("%q: got error %v, want ErrSyntax", src, err)
+		}
+	}
+}
+
+func TestVars(t *testing.T) {
+	expr := MustParse("rsi < 30 and close crosses above sma.200 or abs(rsi - prev(rsi)) > 10")
+
+	vars := expr.Vars()
+	sort.Strings(vars)
+
+	want := []string{"close", "rsi", "sma.200"}
+	if len(vars) != len(want) {
+		t.Fatalf("got %v, want %v", vars, want)
+	}
+	for i := range want {
+		if vars[i] != want[i] {
+			t.Fatalf("got %v, want %v", vars, want)
+		}
+	}
+}
+
+func TestEngine(t *testing.T) {
+	engine := NewEngine()
+	engine.TrackFunc("level", func(bar fta.Bar) fta.DType { return 10 })
+
+	if err := engine.AddExpr("up", "close crosses above level"); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Add(Alert{Name: "high", Condition: MustParse("close > level"), EveryBar: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddExpr("up", "close > 0"); err == nil {
+		t.Fatal("duplicate alert is registered")
+	}
+	if err := engine.AddExpr("bad", "close > sma"); err == nil {
+		t.Fatal("alert of unknown value is registered")
+	}
+
+	var events []string
+	engine.Handle(func(e Event) error {
+		events = append(events, e.Alert)
+		return nil
+	})
+
+	for _, c := range []fta.DType{9, 11, 12, 8, 13} {
+		if err := engine.OnBar(fta.Bar{Close: c}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"up", "high", "high", "up", "high"}
+	if len(events) != len(want) {
+		t.Fatalf("got events %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("got events %v, want %v", events, want)
+		}
+	}
+}
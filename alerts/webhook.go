@@ -0,0 +1,43 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// errStatus is returned by webhooks on unsuccessful responses.
+var errStatus = errors.New("unexpected status")
+
+// Webhook returns the handler posting events as JSON to the url, nil client means http.DefaultClient.
+// The payload is like {"alert":"oversold","time":"2021-01-02T15:04:05Z","values":{"close":101.5,"rsi":29.1}}.
+// Responses with status other than 2xx are errors.
+func Webhook(url string, client *http.Client) Handler {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(e Event) error {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("webhook: marshal: %w", err)
+		}
+
+		resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("webhook: %w", err)
+		}
+		defer resp.Body.Close()
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook: %w: %s", errStatus, resp.Status)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,183 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// FillMethod is the method of filling values missing at the target timestamps.
+type FillMethod int
+
+const (
+	// FillNone leaves missing values as NaNs.
+	FillNone FillMethod = iota
+	// FillForward propagates the last known value forward.
+	FillForward
+	// FillBackward uses the next known value.
+	FillBackward
+)
+
+// Reindex conforms column to the timestamps of ref.
+// Values at timestamps that are not present in column are filled according to method.
+// Indices of both series must be sorted in ascending order.
+func Reindex(column, ref series.Data, method FillMethod) (reindexed series.Data) {
+	return reindex(column, ref.Freq(), ref.Index(), method)
+}
+
+// TimeShift returns copy of column with index moved by lag nanoseconds.
+// Positive lag delays the series, negative lag moves it forward in time.
+// Unlike Shift it doesn't depend on the number of samples, so it works with gaps in the index.
+func TimeShift(column series.Data, lag int64) (shifted series.Data) {
+	index := append([]int64(nil), column.Index()...)
+	for i := range index {
+		index[i] += lag
+	}
+
+	values := append([]DType(nil), column.Values()...)
+
+	shifted = series.MakeData(column.Freq(), index, values)
+
+	return shifted
+}
+
+// Lag aligns column to the timestamps of ref delayed by lag nanoseconds:
+// the value at time t of the result is the value of column at time t-lag.
+// Series may have different frequencies or gaps, missing values are filled according to method.
+func Lag(column, ref series.Data, lag int64, method FillMethod) (lagged series.Data) {
+	lagged = Reindex(TimeShift(column, lag), ref, method)
+	return lagged
+}
+
+// Lead aligns column to the timestamps of ref moved forward by lead nanoseconds:
+// the value at time t of the result is the value of column at time t+lead.
+func Lead(column, ref series.Data, lead int64, method FillMethod) (led series.Data) {
+	led = Lag(column, ref, -lead, method)
+	return led
+}
+
+// JoinHow is the method of joining indices of two series.
+type JoinHow int
+
+const (
+	// JoinInner keeps timestamps present in both series.
+	JoinInner JoinHow = iota
+	// JoinOuter keeps timestamps present in any of series.
+	JoinOuter
+	// JoinLeft keeps timestamps of the left series.
+	JoinLeft
+)
+
+// Join aligns two series to the shared index built according to how.
+// Values missing at the shared timestamps are filled according to method.
+// The frequency of the result is the frequency of a. Indices of both series must be sorted in ascending order.
+func Join(a, b series.Data, how JoinHow, method FillMethod) (left, right series.Data) {
+	var index []int64
+
+	switch how {
+	case JoinOuter:
+		index = unionIndex(a.Index(), b.Index())
+	case JoinLeft:
+		index = a.Index()
+	default:
+		index = intersectIndex(a.Index(), b.Index())
+	}
+
+	left = reindex(a, a.Freq(), index, method)
+	right = reindex(b, a.Freq(), index, method)
+
+	return left, right
+}
+
+// reindex conforms column to the sorted index.
+func reindex(column series.Data, freq int64, index []int64, method FillMethod) series.Data {
+	var (
+		srcIndex  = column.Index()
+		srcValues = column.Values()
+
+		dstIndex  = append([]int64(nil), index...)
+		dstValues = make([]DType, len(index))
+	)
+
+	// j is the count of source timestamps less than or equal to the current target timestamp.
+	j := 0
+
+	for i, ts := range dstIndex {
+		for j < len(srcIndex) && srcIndex[j] <= ts {
+			j++
+		}
+
+		switch {
+		case j > 0 && srcIndex[j-1] == ts:
+			dstValues[i] = srcValues[j-1]
+		case method == FillForward && j > 0:
+			dstValues[i] = srcValues[j-1]
+		case method == FillBackward && j < len(srcIndex):
+			dstValues[i] = srcValues[j]
+		default:
+			dstValues[i] = math.NaN()
+		}
+	}
+
+	return series.MakeData(freq, dstIndex, dstValues)
+}
+
+// Dropna returns copy of column without NA values (NaN and ±Inf, see series.IsNA),
+// the index is compacted accordingly. Columns without index stay without index.
+func Dropna(column series.Data) series.Data {
+	var (
+		index  = column.Index()
+		values = column.Values()
+
+		dstIndex  []int64
+		dstValues = make([]DType, 0, len(values))
+	)
+
+	if index != nil {
+		dstIndex = make([]int64, 0, len(index))
+	}
+
+	for i, v := range values {
+		if series.IsNA(v) {
+			continue
+		}
+		if index != nil {
+			dstIndex = append(dstIndex, index[i])
+		}
+		dstValues = append(dstValues, v)
+	}
+
+	if index == nil {
+		return series.MakeValues(dstValues)
+	}
+
+	return series.MakeData(column.Freq(), dstIndex, dstValues)
+}
+
+// unionIndex merges two sorted indices dropping duplicates.
+func unionIndex(a, b []int64) []int64 {
+	index := make([]int64, 0, len(a)+len(b))
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		var ts int64
+
+		switch {
+		case j >= len(b) || (i < len(a) && a[i] < b[j]):
+			ts = a[i]
+			i++
+		case i >= len(a) || b[j] < a[i]:
+			ts = b[j]
+			j++
+		default:
+			ts = a[i]
+			i++
+			j++
+		}
+
+		if len(index) == 0 || index[len(index)-1] != ts {
+			index = append(index, ts)
+		}
+	}
+
+	return index
+}
@@ -0,0 +1,49 @@
+package fta
+
+import "github.com/WinPooh32/series"
+
+// Backend computes indicators which have exact equivalents in other libraries.
+// SMA, WMA, RSIWilder, RollingMax and RollingMin dispatch to DefaultBackend,
+// so other indicators built on top of them use the backend too.
+type Backend interface {
+	// Name returns the name of the backend.
+	Name() string
+
+	SMA(column series.Data, period int) series.Data
+	WMA(column series.Data, period int) series.Data
+	RSIWilder(column series.Data, period int) series.Data
+	RollingMax(column series.Data, period int) series.Data
+	RollingMin(column series.Data, period int) series.Data
+}
+
+// DefaultBackend is the backend used by indicators.
+// It's GoBackend unless the package is built with talib tag, then it's TALibBackend.
+// It's not safe to change the backend concurrently with indicators calls.
+var DefaultBackend Backend = GoBackend{}
+
+// GoBackend is the pure Go implementation of indicators.
+type GoBackend struct{}
+
+func (GoBackend) Name() string {
+	return "go"
+}
+
+func (GoBackend) SMA(column series.Data, period int) series.Data {
+	return goSMA(column, period)
+}
+
+func (GoBackend) WMA(column series.Data, period int) series.Data {
+	return goWMA(column, period)
+}
+
+func (GoBackend) RSIWilder(column series.Data, period int) series.Data {
+	return goRSIWilder(column, period)
+}
+
+func (GoBackend) RollingMax(column series.Data, period int) series.Data {
+	return goRollingMax(column, period)
+}
+
+func (GoBackend) RollingMin(column series.Data, period int) series.Data {
+	return goRollingMin(column, period)
+}
@@ -0,0 +1,106 @@
+//go:build talib && cgo
+// +build talib,cgo
+
+package fta
+
+/*
+#cgo LDFLAGS: -lta_lib -lm
+#include <ta-lib/ta_libc.h>
+*/
+import "C"
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+func init() {
+	if C.TA_Initialize() == C.TA_SUCCESS {
+		DefaultBackend = TALibBackend{}
+	}
+}
+
+// TALibBackend computes indicators by the C TA-Lib library for the bit-exact parity with it.
+// It's available when the package is built with talib tag and cgo enabled.
+//
+// TA-Lib doesn't handle NaN values and series shorter than the period,
+// so GoBackend is used for such inputs.
+type TALibBackend struct{}
+
+func (TALibBackend) Name() string {
+	return "ta-lib"
+}
+
+func (TALibBackend) SMA(column series.Data, period int) series.Data {
+	return talibCall(column, period, GoBackend{}.SMA, func(end C.int, in *C.double, beg, nb *C.int, out *C.double) C.TA_RetCode {
+		return C.TA_SMA(0, end, in, C.int(period), beg, nb, out)
+	})
+}
+
+func (TALibBackend) WMA(column series.Data, period int) series.Data {
+	return talibCall(column, period, GoBackend{}.WMA, func(end C.int, in *C.double, beg, nb *C.int, out *C.double) C.TA_RetCode {
+		return C.TA_WMA(0, end, in, C.int(period), beg, nb, out)
+	})
+}
+
+func (TALibBackend) RSIWilder(column series.Data, period int) series.Data {
+	return talibCall(column, period, GoBackend{}.RSIWilder, func(end C.int, in *C.double, beg, nb *C.int, out *C.double) C.TA_RetCode {
+		return C.TA_RSI(0, end, in, C.int(period), beg, nb, out)
+	})
+}
+
+func (TALibBackend) RollingMax(column series.Data, period int) series.Data {
+	return talibCall(column, period, GoBackend{}.RollingMax, func(end C.int, in *C.double, beg, nb *C.int, out *C.double) C.TA_RetCode {
+		return C.TA_MAX(0, end, in, C.int(period), beg, nb, out)
+	})
+}
+
+func (TALibBackend) RollingMin(column series.Data, period int) series.Data {
+	return talibCall(column, period, GoBackend{}.RollingMin, func(end C.int, in *C.double, beg, nb *C.int, out *C.double) C.TA_RetCode {
+		return C.TA_MIN(0, end, in, C.int(period), beg, nb, out)
+	})
+}
+
+type talibFunc func(end C.int, in *C.double, beg, nb *C.int, out *C.double) C.TA_RetCode
+
+// talibCall runs TA-Lib function over the whole column and places its output at the end of the result,
+// leading values are NaN. Fallback is called when TA-Lib can't process the column.
+func talibCall(column series.Data, period int, fallback func(series.Data, int) series.Data, fn talibFunc) series.Data {
+	values := column.Values()
+
+	if period < 1 || len(values) < period {
+		return fallback(column, period)
+	}
+
+	in := make([]C.double, len(values))
+	for i, v := range values {
+		if series.IsNA(v) {
+			return fallback(column, period)
+		}
+		in[i] = C.double(v)
+	}
+
+	var (
+		out    = make([]C.double, len(values))
+		beg, n C.int
+	)
+
+	if fn(C.int(len(values)-1), &in[0], &beg, &n, &out[0]) != C.TA_SUCCESS {
+		return fallback(column, period)
+	}
+
+	var (
+		result = column.Clone()
+		dst    = result.Values()
+	)
+
+	for i := range dst {
+		if j := i - int(beg); j >= 0 && j < int(n) {
+			dst[i] = DType(out[j])
+		} else {
+			dst[i] = math.NaN()
+		}
+	}
+
+	return result
+}
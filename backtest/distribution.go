@@ -0,0 +1,164 @@
+package backtest
+
+import (
+	"sort"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// Omega returns the Omega ratio: the sum of returns above the threshold divided by the sum of shortfalls below it.
+// NaN returns are skipped.
+func Omega(returns series.Data, threshold fta.DType) fta.DType {
+	return omega(returns.Values(), threshold)
+}
+
+// TailRatio returns the ratio of the 95th percentile of returns to the absolute 5th percentile.
+// Values above 1 mean that right tail is fatter than left one. NaN returns are skipped.
+func TailRatio(returns series.Data) fta.DType {
+	return tailRatio(returns.Values())
+}
+
+// Skewness returns the sample skewness of returns adjusted for bias like pandas does. NaN returns are skipped.
+func Skewness(returns series.Data) fta.DType {
+	return skewness(returns.Values())
+}
+
+// Kurtosis returns the sample excess kurtosis of returns adjusted for bias like pandas does.
+// Normal distribution has zero excess kurtosis. NaN returns are skipped.
+func Kurtosis(returns series.Data) fta.DType {
+	return kurtosis(returns.Values())
+}
+
+// RollingOmega returns the Omega ratio over the rolling window.
+func RollingOmega(returns series.Data, period int, threshold fta.DType) series.Data {
+	return rolling(returns, period, func(window []fta.DType) fta.DType {
+		return omega(window, threshold)
+	})
+}
+
+// RollingTailRatio returns the tail ratio over the rolling window.
+func RollingTailRatio(returns series.Data, period int) series.Data {
+	return rolling(returns, period, tailRatio)
+}
+
+// RollingSkewness returns the skewness over the rolling window.
+func RollingSkewness(returns series.Data, period int) series.Data {
+	return rolling(returns, period, skewness)
+}
+
+// RollingKurtosis returns the excess kurtosis over the rolling window.
+func RollingKurtosis(returns series.Data, period int) series.Data {
+	return rolling(returns, period, kurtosis)
+}
+
+// rolling applies fn to values of every window, the first period-1 values are NaN.
+func rolling(returns series.Data, period int, fn func(window []fta.DType) fta.DType) series.Data {
+	if returns.Len() < period {
+		return nanLike(returns)
+	}
+
+	return returns.Rolling(period).Apply(func(window series.Data) fta.DType {
+		return fn(window.Values())
+	})
+}
+
+func omega(values []fta.DType, threshold fta.DType) fta.DType {
+	var gain, loss fta.DType
+
+	for _, v := range values {
+		switch d := v - threshold; {
+		case series.IsNA(v):
+		case d > 0:
+			gain += d
+		default:
+			loss -= d
+		}
+	}
+
+	if gain == 0 && loss == 0 {
+		return math.NaN()
+	}
+
+	return gain / loss
+}
+
+func tailRatio(values []fta.DType) fta.DType {
+	sorted := make([]fta.DType, 0, len(values))
+	for _, v := range values {
+		if !series.IsNA(v) {
+			sorted = append(sorted, v)
+		}
+	}
+
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+
+	sort.Sort(series.DTypeSlice(sorted))
+
+	return math.Abs(percentile(sorted, 0.95)) / math.Abs(percentile(sorted, 0.05))
+}
+
+// percentile returns q-th quantile of sorted values using linear interpolation.
+func percentile(sorted []fta.DType, q fta.DType) fta.DType {
+	pos := q * fta.DType(len(sorted)-1)
+	l := int(pos)
+
+	if l >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+
+	return sorted[l] + (pos-fta.DType(l))*(sorted[l+1]-sorted[l])
+}
+
+// moments returns the number of not NaN values and their central moments of 2nd, 3rd and 4th orders.
+func moments(values []fta.DType) (n int, m2, m3, m4 fta.DType) {
+	var sum fta.DType
+
+	for _, v := range values {
+		if !series.IsNA(v) {
+			sum += v
+			n++
+		}
+	}
+
+	mean := sum / fta.DType(n)
+
+	for _, v := range values {
+		if series.IsNA(v) {
+			continue
+		}
+		d := v - mean
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+
+	count := fta.DType(n)
+
+	return n, m2 / count, m3 / count, m4 / count
+}
+
+func skewness(values []fta.DType) fta.DType {
+	n, m2, m3, _ := moments(values)
+	if n < 3 || m2 == 0 {
+		return math.NaN()
+	}
+
+	count := fta.DType(n)
+
+	return math.Sqrt(count*(count-1)) / (count - 2) * m3 / math.Pow(m2, 1.5)
+}
+
+func kurtosis(values []fta.DType) fta.DType {
+	n, m2, _, m4 := moments(values)
+	if n < 4 || m2 == 0 {
+		return math.NaN()
+	}
+
+	count := fta.DType(n)
+
+	return (count - 1) / ((count - 2) * (count - 3)) * ((count+1)*m4/(m2*m2) - 3*(count-1))
+}
@@ -0,0 +1,55 @@
+package backtest
+
+import (
+	stdmath "math"
+	"testing"
+
+	"github.com/WinPooh32/fta"
+)
+
+func TestDistribution(t *testing.T) {
+	nan := fta.DType(stdmath.NaN())
+
+	tail := make([]fta.DType, 21)
+	for i := range tail {
+		tail[i] = 0.01 * fta.DType(i-5)
+	}
+
+	checks := []struct {
+		name      string
+		got, want fta.DType
+	}{
+		{"Omega", Omega(returnsAt(0, 0.02, -0.01, 0.03, nan, -0.02), 0), 0.05 / 0.03},
+		{"Omega threshold", Omega(returnsAt(0, 0.02, -0.01, 0.03), 0.01), 0.03 / 0.02},
+		{"Omega flat", Omega(returnsAt(0, 0.01, 0.01), 0.01), nan},
+		{"TailRatio", TailRatio(returnsAt(0, tail...)), 0.14 / 0.04},
+		{"TailRatio empty", TailRatio(returnsAt(0, nan)), nan},
+		{"Skewness", Skewness(returnsAt(0, 1, 2, nan, 3, 10)), 1.763632614803888},
+		{"Skewness short", Skewness(returnsAt(0, 1, 2)), nan},
+		{"Kurtosis", Kurtosis(returnsAt(0, 1, 2, 3, 4, 10)), 3.152},
+		{"Kurtosis flat", Kurtosis(returnsAt(0, 1, 1, 1, 1)), nan},
+	}
+
+	for _, c := range checks {
+		assertSeries(t, c.name, []fta.DType{c.got}, []fta.DType{c.want})
+	}
+}
+
+func TestRollingDistribution(t *testing.T) {
+	nan := fta.DType(stdmath.NaN())
+	returns := returnsAt(0, 1, 2, 3, 10)
+
+	assertSeries(t, "skewness", RollingSkewness(returns, 3).Values(), []fta.DType{nan, nan, 0, 1.6300591617118863})
+	assertSeries(t, "omega", RollingOmega(returns, 2, 2.5).Values(), []fta.DType{nan, 0, 1, fta.DType(stdmath.Inf(1))})
+
+	// Series shorter than the window have no values.
+	short := returnsAt(0, 0.01, 0.02)
+	for name, got := range map[string][]fta.DType{
+		"omega":    RollingOmega(short, 5, 0).Values(),
+		"tail":     RollingTailRatio(short, 5).Values(),
+		"skewness": RollingSkewness(short, 5).Values(),
+		"kurtosis": RollingKurtosis(short, 5).Values(),
+	} {
+		assertSeries(t, name, got, []fta.DType{nan, nan})
+	}
+}
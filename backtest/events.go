@@ -0,0 +1,153 @@
+package backtest
+
+import (
+	"sort"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// ReturnStats are statistics of forward returns.
+type ReturnStats struct {
+	// Count is the number of returns.
+	Count int
+	// Mean, Std and quantiles of returns. Std is the sample standard deviation.
+	Mean, Std, Q05, Q25, Median, Q75, Q95 fta.DType
+	// HitRate is the fraction of positive returns.
+	HitRate fta.DType
+}
+
+// HorizonStats compares forward returns after events with forward returns after all bars at the horizon.
+type HorizonStats struct {
+	// Horizon is the number of bars after the event.
+	Horizon int
+	// Events are statistics of returns after events, Baseline are statistics of returns after all bars.
+	Events, Baseline ReturnStats
+	// Excess is the difference of mean returns after events and after all bars.
+	Excess fta.DType
+	// TStat is Welch's t-statistic of the difference of means, absolute values above 2 suggest the edge.
+	TStat fta.DType
+}
+
+// EventReport is the result of the event study.
+type EventReport struct {
+	Horizons []HorizonStats
+	// Returns are forward returns of every horizon after every event in order of events.
+	// Returns are NaN when the horizon is beyond the data.
+	Returns [][]fta.DType
+}
+
+// EventStudy computes forward close returns after events at horizons and compares them with the baseline
+// of forward returns after every bar. Events are timestamps in nanoseconds since epoch,
+// the event happens at the first bar not earlier than its timestamp and the return is counted from the close of that bar.
+// Bars of ohlcv must be sorted by time.
+func EventStudy(ohlcv fta.OHLCV, events []int64, horizons []int) (study EventReport) {
+	var (
+		index = ohlcv.Close.Index()
+		c     = ohlcv.Close.Values()
+	)
+
+	forward := func(i, h int) fta.DType {
+		if i < 0 || i+h >= len(c) {
+			return math.NaN()
+		}
+		return c[i+h]/c[i] - 1
+	}
+
+	positions := make([]int, len(events))
+	for k, ts := range events {
+		i := sort.Search(len(index), func(j int) bool { return index[j] >= ts })
+		if i == len(index) {
+			i = -1
+		}
+		positions[k] = i
+	}
+
+	study.Horizons = make([]HorizonStats, len(horizons))
+	study.Returns = make([][]fta.DType, len(horizons))
+
+	for n, h := range horizons {
+		returns := make([]fta.DType, len(events))
+		for k, i := range positions {
+			returns[k] = forward(i, h)
+		}
+
+		baseline := make([]fta.DType, len(c))
+		for i := range c {
+			baseline[i] = forward(i, h)
+		}
+
+		stats := HorizonStats{
+			Horizon:  h,
+			Events:   returnStats(returns),
+			Baseline: returnStats(baseline),
+		}
+
+		stats.Excess = stats.Events.Mean - stats.Baseline.Mean
+		stats.TStat = welch(stats.Events, stats.Baseline)
+
+		study.Horizons[n] = stats
+		study.Returns[n] = returns
+	}
+
+	return study
+}
+
+// returnStats computes statistics of not NaN returns.
+func returnStats(returns []fta.DType) (stats ReturnStats) {
+	sorted := make([]fta.DType, 0, len(returns))
+	for _, r := range returns {
+		if !series.IsNA(r) {
+			sorted = append(sorted, r)
+		}
+	}
+
+	stats.Count = len(sorted)
+
+	if len(sorted) == 0 {
+		nan := math.NaN()
+		return ReturnStats{Mean: nan, Std: nan, Q05: nan, Q25: nan, Median: nan, Q75: nan, Q95: nan, HitRate: nan}
+	}
+
+	sort.Sort(series.DTypeSlice(sorted))
+
+	var hits, ss fta.DType
+
+	stats.Mean = mean(sorted)
+
+	for _, r := range sorted {
+		if r > 0 {
+			hits++
+		}
+		ss += (r - stats.Mean) * (r - stats.Mean)
+	}
+
+	stats.Std = math.NaN()
+	if len(sorted) > 1 {
+		stats.Std = math.Sqrt(ss / fta.DType(len(sorted)-1))
+	}
+
+	stats.Q05 = percentile(sorted, 0.05)
+	stats.Q25 = percentile(sorted, 0.25)
+	stats.Median = percentile(sorted, 0.5)
+	stats.Q75 = percentile(sorted, 0.75)
+	stats.Q95 = percentile(sorted, 0.95)
+	stats.HitRate = hits / fta.DType(len(sorted))
+
+	return stats
+}
+
+// welch returns Welch's t-statistic of the difference of means of a and b.
+func welch(a, b ReturnStats) fta.DType {
+	if a.Count < 2 || b.Count < 2 {
+		return math.NaN()
+	}
+
+	se := math.Sqrt(a.Std*a.Std/fta.DType(a.Count) + b.Std*b.Std/fta.DType(b.Count))
+	if se == 0 {
+		return math.NaN()
+	}
+
+	return (a.Mean - b.Mean) / se
+}
@@ -0,0 +1,222 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+)
+
+// Layout is the layout of exported trades.
+type Layout int
+
+const (
+	// LayoutFills writes each trade as two fills: time, side (buy or sell), price and quantity, ordered by time.
+	LayoutFills Layout = iota
+	// LayoutTradingView writes trades like "List of trades" export of TradingView strategy tester.
+	LayoutTradingView
+)
+
+// WriteTradesCSV writes trades to w as csv with header. Times are formatted in UTC.
+func WriteTradesCSV(w io.Writer, trades []Trade, layout Layout) error {
+	writer := csv.NewWriter(w)
+
+	var err error
+
+	switch layout {
+	case LayoutTradingView:
+		err = writeTradingView(writer, trades)
+	default:
+		err = writeFills(writer, trades)
+	}
+
+	if err != nil {
+		return fmt.Errorf("write trades: %w", err)
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("write trades: %w", err)
+	}
+
+	return nil
+}
+
+// WriteTradesJSON writes trades to w as JSON array of objects. Times are in RFC 3339 format in UTC.
+func WriteTradesJSON(w io.Writer, trades []Trade) error {
+	type jsonTrade struct {
+		Side       string    `json:"side"`
+		EntryTime  time.Time `json:"entry_time"`
+		EntryPrice float64   `json:"entry_price"`
+		ExitTime   time.Time `json:"exit_time"`
+		ExitPrice  float64   `json:"exit_price"`
+		Size       float64   `json:"size"`
+		PnL        float64   `json:"pnl"`
+	}
+
+	rows := make([]jsonTrade, len(trades))
+	for i, t := range trades {
+		rows[i] = jsonTrade{
+			Side:       t.Side.String(),
+			EntryTime:  time.Unix(0, t.EntryTime).UTC(),
+			EntryPrice: float64(t.EntryPrice),
+			ExitTime:   time.Unix(0, t.ExitTime).UTC(),
+			ExitPrice:  float64(t.ExitPrice),
+			Size:       float64(t.Size),
+			PnL:        float64(t.PnL()),
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(rows); err != nil {
+		return fmt.Errorf("write trades: %w", err)
+	}
+
+	return nil
+}
+
+// WriteSignalsCSV writes triggered signals to w as csv rows of time, signal name and price.
+// Signal is triggered when its value isn't zero or NaN, e.g. outputs of BREAKOUT.
+// Prices are taken at the same positions, so series must be of the same length.
+func WriteSignalsCSV(w io.Writer, name string, signal, price series.Data) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"time", "signal", "price"}); err != nil {
+		return fmt.Errorf("write signals: %w", err)
+	}
+
+	var (
+		index  = signal.Index()
+		prices = price.Values()
+	)
+
+	for i, v := range signal.Values() {
+		if v == 0 || series.IsNA(v) {
+			continue
+		}
+
+		record := []string{
+			time.Unix(0, index[i]).UTC().Format(time.RFC3339Nano),
+			name,
+			formatFloat(prices[i]),
+		}
+
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("write signals: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("write signals: %w", err)
+	}
+
+	return nil
+}
+
+func writeFills(writer *csv.Writer, trades []Trade) error {
+	type fill struct {
+		time  int64
+		side  string
+		price fta.DType
+		size  fta.DType
+	}
+
+	fills := make([]fill, 0, 2*len(trades))
+
+	for _, t := range trades {
+		entry, exit := "buy", "sell"
+		if t.Side == Short {
+			entry, exit = exit, entry
+		}
+
+		fills = append(fills,
+			fill{t.EntryTime, entry, t.EntryPrice, t.Size},
+			fill{t.ExitTime, exit, t.ExitPrice, t.Size},
+		)
+	}
+
+	sort.SliceStable(fills, func(i, j int) bool { return fills[i].time < fills[j].time })
+
+	if err := writer.Write([]string{"time", "side", "price", "qty"}); err != nil {
+		return err
+	}
+
+	for _, f := range fills {
+		record := []string{
+			time.Unix(0, f.time).UTC().Format(time.RFC3339Nano),
+			f.side,
+			formatFloat(f.price),
+			formatFloat(f.size),
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeTradingView(writer *csv.Writer, trades []Trade) error {
+	const layout = "2006-01-02 15:04"
+
+	header := []string{"Trade #", "Type", "Signal", "Date/Time", "Price", "Contracts", "Profit", "Profit %", "Cum. Profit"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	var cum fta.DType
+
+	for i, t := range trades {
+		var (
+			num     = strconv.Itoa(i + 1)
+			side    = "Long"
+			pnl     = t.PnL()
+			percent = t.Return() * 100
+		)
+
+		if t.Side == Short {
+			side = "Short"
+		}
+
+		cum += pnl
+
+		// TradingView lists the exit row before the entry row of each trade.
+		records := [][]string{
+			{
+				num, "Exit " + side, "Exit",
+				time.Unix(0, t.ExitTime).UTC().Format(layout),
+				formatFloat(t.ExitPrice), formatFloat(t.Size),
+				formatFloat(pnl), formatFloat(percent), formatFloat(cum),
+			},
+			{
+				num, "Entry " + side, "Entry",
+				time.Unix(0, t.EntryTime).UTC().Format(layout),
+				formatFloat(t.EntryPrice), formatFloat(t.Size),
+				formatFloat(pnl), formatFloat(percent), formatFloat(cum),
+			},
+		}
+
+		if err := writer.WriteAll(records); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatFloat(v fta.DType) string {
+	bitSize := 64
+	if series.EnabledFloat32 {
+		bitSize = 32
+	}
+	return strconv.FormatFloat(float64(v), 'f', -1, bitSize)
+}
@@ -0,0 +1,116 @@
+package backtest
+
+import (
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// Relative is the performance of the strategy relative to the benchmark.
+type Relative struct {
+	// Alpha is the annualized Jensen's alpha with zero risk-free rate.
+	Alpha fta.DType
+	// Beta is the sensitivity of strategy returns to benchmark returns.
+	Beta fta.DType
+	// TrackingError is the annualized standard deviation of active returns.
+	TrackingError fta.DType
+	// InformationRatio is the annualized mean active return divided by the tracking error.
+	InformationRatio fta.DType
+	// UpCapture is the mean strategy return divided by the mean benchmark return over periods when the benchmark rises.
+	UpCapture fta.DType
+	// DownCapture is the same as UpCapture over periods when the benchmark falls.
+	DownCapture fta.DType
+}
+
+// RelativeMetrics compares per-period returns of the strategy with returns of the benchmark.
+// Series are joined by timestamps, periods where any of returns is NaN are skipped.
+// PeriodsPerYear annualizes metrics, e.g. 252 for daily returns of stocks or 365 for crypto.
+func RelativeMetrics(strategy, benchmark series.Data, periodsPerYear int) (rel Relative) {
+	s, b := pairs(strategy, benchmark)
+
+	nan := math.NaN()
+	rel = Relative{nan, nan, nan, nan, nan, nan}
+
+	if len(s) < 2 {
+		return rel
+	}
+
+	var (
+		n     = fta.DType(len(s))
+		years = fta.DType(periodsPerYear)
+
+		meanS, meanB = mean(s), mean(b)
+
+		cov, varB, meanActive, varActive fta.DType
+
+		upS, upB, downS, downB fta.DType
+	)
+
+	for i := range s {
+		cov += (s[i] - meanS) * (b[i] - meanB)
+		varB += (b[i] - meanB) * (b[i] - meanB)
+		meanActive += s[i] - b[i]
+
+		switch {
+		case b[i] > 0:
+			upS += s[i]
+			upB += b[i]
+		case b[i] < 0:
+			downS += s[i]
+			downB += b[i]
+		}
+	}
+
+	meanActive /= n
+
+	for i := range s {
+		d := s[i] - b[i] - meanActive
+		varActive += d * d
+	}
+
+	rel.Beta = cov / varB
+	rel.Alpha = (meanS - rel.Beta*meanB) * years
+	rel.TrackingError = math.Sqrt(varActive/(n-1)) * math.Sqrt(years)
+	rel.InformationRatio = meanActive * years / rel.TrackingError
+
+	// Counts of periods cancel out in the ratio of means.
+	if upB != 0 {
+		rel.UpCapture = upS / upB
+	}
+	if downB != 0 {
+		rel.DownCapture = downS / downB
+	}
+
+	return rel
+}
+
+// pairs joins two series by timestamps and returns values of pairs without NaN.
+func pairs(a, b series.Data) (x, y []fta.DType) {
+	a, b = fta.Join(a, b, fta.JoinInner, fta.FillNone)
+
+	var (
+		av = a.Values()
+		bv = b.Values()
+	)
+
+	x = make([]fta.DType, 0, len(av))
+	y = make([]fta.DType, 0, len(bv))
+
+	for i := range av {
+		if series.IsNA(av[i]) || series.IsNA(bv[i]) {
+			continue
+		}
+		x = append(x, av[i])
+		y = append(y, bv[i])
+	}
+
+	return x, y
+}
+
+func mean(values []fta.DType) fta.DType {
+	var sum fta.DType
+	for _, v := range values {
+		sum += v
+	}
+	return sum / fta.DType(len(values))
+}
@@ -0,0 +1,79 @@
+package backtest
+
+import (
+	stdmath "math"
+	"testing"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+)
+
+// returnsAt returns the series of values with the index start, start+1...
+func returnsAt(start int64, values ...fta.DType) series.Data {
+	index := make([]int64, len(values))
+	for i := range index {
+		index[i] = start + int64(i)
+	}
+	return series.MakeData(1, index, values)
+}
+
+func TestRelativeMetrics(t *testing.T) {
+	var (
+		nan       = fta.DType(stdmath.NaN())
+		benchmark = []fta.DType{0.01, -0.02, 0.03, -0.01, 0.02}
+		strategy  = make([]fta.DType, len(benchmark))
+	)
+
+	// The strategy is the leveraged benchmark with the constant edge.
+	for i, b := range benchmark {
+		strategy[i] = 2*b + 0.001
+	}
+
+	var meanB, ss float64
+	for _, b := range benchmark {
+		meanB += float64(b) / 5
+	}
+	for _, b := range benchmark {
+		ss += (float64(b) - meanB) * (float64(b) - meanB)
+	}
+	trackingError := stdmath.Sqrt(ss/4) * stdmath.Sqrt(252)
+
+	// The strategy has one more period and the benchmark has the NaN period, both are skipped.
+	rel := RelativeMetrics(
+		returnsAt(0, append(strategy, 0.5)...),
+		returnsAt(0, append(benchmark, nan)...),
+		252,
+	)
+
+	checks := []struct {
+		name      string
+		got, want fta.DType
+	}{
+		{"Beta", rel.Beta, 2},
+		{"Alpha", rel.Alpha, 0.001 * 252},
+		{"TrackingError", rel.TrackingError, fta.DType(trackingError)},
+		{"InformationRatio", rel.InformationRatio, fta.DType((meanB + 0.001) * 252 / trackingError)},
+		{"UpCapture", rel.UpCapture, 0.123 / 0.06},
+		{"DownCapture", rel.DownCapture, -0.058 / -0.03},
+	}
+
+	for _, c := range checks {
+		if stdmath.Abs(float64(c.got-c.want)) > 1e-4*stdmath.Max(1, stdmath.Abs(float64(c.want))) {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestRelativeMetricsJoin(t *testing.T) {
+	// Only two periods overlap by timestamps.
+	rel := RelativeMetrics(returnsAt(0, 0.02, 0.04, 0.02), returnsAt(1, 0.02, 0.01, 0.05), 1)
+
+	if stdmath.Abs(float64(rel.Beta-2)) > 1e-4 {
+		t.Errorf("got beta %v, want 2", rel.Beta)
+	}
+
+	rel = RelativeMetrics(returnsAt(0, 0.02), returnsAt(0, 0.01), 252)
+	if !series.IsNA(rel.Beta) || !series.IsNA(rel.Alpha) || !series.IsNA(rel.UpCapture) {
+		t.Errorf("got %+v for a single period, want NaN", rel)
+	}
+}
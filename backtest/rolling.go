@@ -0,0 +1,72 @@
+package backtest
+
+import (
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// Equity returns the cumulative growth of one unit of capital by per-period returns.
+// NaN returns are treated as zero returns.
+func Equity(returns series.Data) (equity series.Data) {
+	equity = returns.Clone()
+
+	var (
+		values = equity.Values()
+		growth = fta.DType(1)
+	)
+
+	for i, r := range values {
+		if !series.IsNA(r) {
+			growth *= 1 + r
+		}
+		values[i] = growth
+	}
+
+	return equity
+}
+
+// RollingSharpe returns the annualized Sharpe ratio with zero risk-free rate over the rolling window.
+func RollingSharpe(returns series.Data, period, periodsPerYear int) (sharpe series.Data) {
+	if returns.Len() < period {
+		return nanLike(returns)
+	}
+
+	var (
+		ma  = returns.Rolling(period).Mean()
+		std = returns.Rolling(period).Std(ma, 1)
+	)
+
+	sharpe = ma.Div(std).MulScalar(math.Sqrt(fta.DType(periodsPerYear)))
+
+	return sharpe
+}
+
+// RollingBeta returns the beta of strategy returns to benchmark returns over the rolling window.
+// Series are joined by timestamps of the strategy, missing benchmark returns are NaN.
+func RollingBeta(strategy, benchmark series.Data, period int) (beta series.Data) {
+	strategy, benchmark = fta.Join(strategy, benchmark, fta.JoinLeft, fta.FillNone)
+	beta, _, _, _ = fta.RollingOLS(strategy, benchmark, period)
+	return beta
+}
+
+// RollingDrawdown returns the relative decline of equity from its peak within the rolling window.
+// Values are not positive, e.g. -0.2 is the drawdown of 20%.
+func RollingDrawdown(returns series.Data, period int) (drawdown series.Data) {
+	equity := Equity(returns)
+
+	drawdown = equity.Clone().Div(fta.RollingMax(equity, period)).SubScalar(1)
+
+	return drawdown
+}
+
+// nanLike returns the clone of the series filled by NaNs, e.g. for series shorter than the rolling window:
+// they have no full windows, and series.Window would index out of the data.
+func nanLike(column series.Data) series.Data {
+	result := column.Clone()
+	values := result.Values()
+	for i := range values {
+		values[i] = math.NaN()
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package backtest
+
+import (
+	stdmath "math"
+	"testing"
+
+	"github.com/WinPooh32/fta"
+)
+
+// assertSeries compares values with tolerance, NaN values are equal.
+func assertSeries(t *testing.T, name string, got []fta.DType, want []fta.DType) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %v, want %v", name, got, want)
+	}
+	for i := range want {
+		g, w := float64(got[i]), float64(want[i])
+		if stdmath.IsNaN(g) != stdmath.IsNaN(w) || stdmath.Abs(g-w) > 1e-4*stdmath.Max(1, stdmath.Abs(w)) {
+			t.Fatalf("%s: at %d got %v, want %v\ngot:  %v\nwant: %v", name, i, g, w, got, want)
+		}
+	}
+}
+
+func TestEquity(t *testing.T) {
+	nan := fta.DType(stdmath.NaN())
+
+	equity := Equity(returnsAt(0, 0.1, nan, -0.5, 1))
+	assertSeries(t, "equity", equity.Values(), []fta.DType{1.1, 1.1, 0.55, 1.1})
+
+	drawdown := RollingDrawdown(returnsAt(0, 0.1, nan, -0.5, 1), 2)
+	assertSeries(t, "drawdown", drawdown.Values(), []fta.DType{nan, 0, -0.5, 0})
+}
+
+func TestRollingSharpe(t *testing.T) {
+	nan := fta.DType(stdmath.NaN())
+
+	// Mean 0.02 and sample deviation 0.01 of every window.
+	sharpe := RollingSharpe(returnsAt(0, 0.01, 0.03, 0.02, 0.01, 0.03), 3, 4)
+	assertSeries(t, "sharpe", sharpe.Values(), []fta.DType{nan, nan, 4, 4, 4})
+
+	sharpe = RollingSharpe(returnsAt(0, 0.01, 0.02), 5, 252)
+	assertSeries(t, "short", sharpe.Values(), []fta.DType{nan, nan})
+}
+
+func TestRollingBeta(t *testing.T) {
+	nan := fta.DType(stdmath.NaN())
+
+	beta := RollingBeta(returnsAt(0, 0.02, -0.04, 0.06, 0.02), returnsAt(0, 0.01, -0.02, 0.03, 0.01), 3)
+	assertSeries(t, "beta", beta.Values(), []fta.DType{nan, nan, 2, 2})
+}
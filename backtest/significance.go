@@ -0,0 +1,193 @@
+package backtest
+
+import (
+	"math/rand"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+)
+
+// Metric scores per-period returns of a strategy, larger is better.
+type Metric func(returns series.Data) fta.DType
+
+// TotalReturn is the metric of compounded return of the whole period.
+func TotalReturn(returns series.Data) fta.DType {
+	equity := Equity(returns).Values()
+	if len(equity) == 0 {
+		return 0
+	}
+	return equity[len(equity)-1] - 1
+}
+
+// Significance is the result of permutation tests of a signal.
+type Significance struct {
+	// Observed is the metric of the signal.
+	Observed fta.DType
+	// Shuffled are metrics of randomly permuted signals, they keep the time in market of each side.
+	Shuffled []fta.DType
+	// RandomEntries are metrics of signals with the same trades placed at random bars.
+	// Trades keep their sides and durations, so the number of trades and time in market match the signal.
+	RandomEntries []fta.DType
+	// ShuffledP and RandomEntriesP are p-values: the probability to get the observed metric or better by chance.
+	ShuffledP, RandomEntriesP fta.DType
+}
+
+// SignalReturns returns per-period returns of trading the close by the signal.
+// Signal is the position held after the bar: positive values are long, negative values are short
+// and zero or NaN values are flat. The position is entered at the close of the signal bar,
+// so the return of the bar is earned by the position of the previous bar.
+func SignalReturns(signal, close series.Data) (returns series.Data) {
+	var (
+		pos = signal.Values()
+		c   = close.Values()
+	)
+
+	returns = close.Clone()
+	r := returns.Values()
+
+	for i := range r {
+		if i == 0 {
+			r[i] = 0
+			continue
+		}
+		r[i] = side(pos[i-1]) * (c[i]/c[i-1] - 1)
+	}
+
+	return returns
+}
+
+// PermutationTest compares metric of the signal with metrics of the same number of shuffled signals
+// and random entries. Nil metric means TotalReturn. The same seed gives the same result.
+// Low p-values, e.g. below 0.05, mean that random signals rarely perform as well, so the edge is unlikely to be luck.
+// Progress receives the number of evaluated random signals out of 2*iterations, nil disables reporting.
+func PermutationTest(signal, close series.Data, metric Metric, iterations int, seed int64, progress fta.ProgressFunc) (sig Significance) {
+	if metric == nil {
+		metric = TotalReturn
+	}
+
+	var (
+		rnd   = rand.New(rand.NewSource(seed))
+		pos   = positions(signal)
+		trial = signal.Clone()
+		dst   = trial.Values()
+
+		tracker = fta.NewProgressTracker(progress, int64(2*iterations))
+	)
+
+	sig.Observed = metric(SignalReturns(signal, close))
+
+	sig.Shuffled = make([]fta.DType, iterations)
+	for i := range sig.Shuffled {
+		copy(dst, pos)
+		rnd.Shuffle(len(dst), func(a, b int) { dst[a], dst[b] = dst[b], dst[a] })
+		sig.Shuffled[i] = metric(SignalReturns(trial, close))
+		tracker.Add(1)
+	}
+
+	var (
+		trades, gaps = runs(pos)
+		order        = make([]int, len(gaps))
+	)
+
+	sig.RandomEntries = make([]fta.DType, iterations)
+	for i := range sig.RandomEntries {
+		rnd.Shuffle(len(trades), func(a, b int) { trades[a], trades[b] = trades[b], trades[a] })
+
+		for j := range order {
+			order[j] = j
+		}
+		rnd.Shuffle(len(order), func(a, b int) { order[a], order[b] = order[b], order[a] })
+
+		// Interleave shuffled gaps and trades, so trades never overlap.
+		k := 0
+		for j, g := range order {
+			for n := 0; n < gaps[g]; n++ {
+				dst[k] = 0
+				k++
+			}
+			if j < len(trades) {
+				for n := 0; n < trades[j].length; n++ {
+					dst[k] = trades[j].side
+					k++
+				}
+			}
+		}
+
+		sig.RandomEntries[i] = metric(SignalReturns(trial, close))
+		tracker.Add(1)
+	}
+
+	tracker.Finish()
+
+	sig.ShuffledP = pValue(sig.Observed, sig.Shuffled)
+	sig.RandomEntriesP = pValue(sig.Observed, sig.RandomEntries)
+
+	return sig
+}
+
+// run is a sequence of bars in the same position.
+type run struct {
+	side   fta.DType
+	length int
+}
+
+// runs splits positions into trades and flat gaps around them.
+// There is one more gap than trades, gaps may be empty.
+func runs(pos []fta.DType) (trades []run, gaps []int) {
+	gap := 0
+
+	for i := 0; i < len(pos); {
+		if pos[i] == 0 {
+			gap++
+			i++
+			continue
+		}
+
+		gaps = append(gaps, gap)
+		gap = 0
+
+		t := run{side: pos[i]}
+		for i < len(pos) && pos[i] == t.side {
+			t.length++
+			i++
+		}
+		trades = append(trades, t)
+	}
+
+	gaps = append(gaps, gap)
+
+	return trades, gaps
+}
+
+// positions converts signal to -1, 0 and 1 positions.
+func positions(signal series.Data) []fta.DType {
+	values := signal.Values()
+	pos := make([]fta.DType, len(values))
+	for i, v := range values {
+		pos[i] = side(v)
+	}
+	return pos
+}
+
+func side(v fta.DType) fta.DType {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// pValue returns the fraction of the distribution not worse than observed with add-one correction,
+// so the p-value is never zero.
+func pValue(observed fta.DType, distribution []fta.DType) fta.DType {
+	count := 1
+	for _, v := range distribution {
+		if v >= observed {
+			count++
+		}
+	}
+	return fta.DType(count) / fta.DType(len(distribution)+1)
+}
@@ -0,0 +1,215 @@
+// Package backtest provides analytics of backtest results: closed trades and returns of strategies.
+package backtest
+
+import (
+	stdmath "math"
+	"sort"
+	"time"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series/math"
+)
+
+// Side is the direction of the trade.
+type Side int
+
+const (
+	// Long profits from rising prices.
+	Long Side = iota
+	// Short profits from falling prices.
+	Short
+)
+
+// String returns name of the side in lower case.
+func (s Side) String() string {
+	if s == Short {
+		return "short"
+	}
+	return "long"
+}
+
+// Trade is a closed position. Times are nanoseconds since epoch.
+type Trade struct {
+	Side                  Side
+	EntryTime, ExitTime   int64
+	EntryPrice, ExitPrice fta.DType
+	// Size is the absolute position size.
+	Size fta.DType
+}
+
+// PnL returns the profit or loss of the trade without fees.
+func (t Trade) PnL() fta.DType {
+	pnl := (t.ExitPrice - t.EntryPrice) * t.Size
+	if t.Side == Short {
+		pnl = -pnl
+	}
+	return pnl
+}
+
+// Return returns the relative profit or loss of the trade.
+func (t Trade) Return() fta.DType {
+	ret := (t.ExitPrice - t.EntryPrice) / t.EntryPrice
+	if t.Side == Short {
+		ret = -ret
+	}
+	return ret
+}
+
+// Duration returns holding time of the trade.
+func (t Trade) Duration() time.Duration {
+	return time.Duration(t.ExitTime - t.EntryTime)
+}
+
+// Excursion is the maximum adverse and favorable excursions of the trade in price units.
+// Both values are not negative.
+type Excursion struct{ MAE, MFE fta.DType }
+
+// Report is the summary of trades.
+type Report struct {
+	Trades, Wins, Losses int
+	// WinRate is the share of profitable trades.
+	WinRate fta.DType
+	// AvgWin is the mean profit of winning trades.
+	AvgWin fta.DType
+	// AvgLoss is the mean loss of losing trades, it's negative.
+	AvgLoss fta.DType
+	// ProfitFactor is the gross profit divided by the absolute gross loss.
+	// It's +Inf without losing trades and NaN without both winning and losing trades.
+	ProfitFactor fta.DType
+	// Expectancy is the mean profit or loss per trade.
+	Expectancy fta.DType
+	// Holding times of trades.
+	AvgHolding, MedianHolding, MinHolding, MaxHolding time.Duration
+	// MaxConsecutiveWins and MaxConsecutiveLosses are the longest series of winning and losing trades.
+	MaxConsecutiveWins, MaxConsecutiveLosses int
+	// Excursions are MAE and MFE of each trade, nil when bars are not provided.
+	Excursions []Excursion
+}
+
+// Analyze returns the report of trades ordered by exit time.
+// Excursions are computed from bars when ohlcv isn't empty.
+// Trades with zero PnL are counted neither as wins nor as losses.
+func Analyze(trades []Trade, ohlcv fta.OHLCV) (report Report) {
+	report.Trades = len(trades)
+
+	if len(trades) == 0 {
+		nan := math.NaN()
+		report.WinRate, report.AvgWin, report.AvgLoss, report.ProfitFactor, report.Expectancy = nan, nan, nan, nan, nan
+		return report
+	}
+
+	var (
+		grossProfit, grossLoss fta.DType
+		wins, losses           int
+
+		holdings = make([]time.Duration, len(trades))
+		total    time.Duration
+	)
+
+	for i, t := range trades {
+		pnl := t.PnL()
+
+		switch {
+		case pnl > 0:
+			grossProfit += pnl
+			report.Wins++
+			wins++
+			losses = 0
+		case pnl < 0:
+			grossLoss += pnl
+			report.Losses++
+			losses++
+			wins = 0
+		default:
+			wins, losses = 0, 0
+		}
+
+		if wins > report.MaxConsecutiveWins {
+			report.MaxConsecutiveWins = wins
+		}
+		if losses > report.MaxConsecutiveLosses {
+			report.MaxConsecutiveLosses = losses
+		}
+
+		holdings[i] = t.Duration()
+		total += holdings[i]
+	}
+
+	n := fta.DType(len(trades))
+
+	report.WinRate = fta.DType(report.Wins) / n
+	report.AvgWin = grossProfit / fta.DType(report.Wins)
+	report.AvgLoss = grossLoss / fta.DType(report.Losses)
+	switch {
+	case grossLoss < 0:
+		report.ProfitFactor = grossProfit / -grossLoss
+	case grossProfit > 0:
+		report.ProfitFactor = fta.DType(stdmath.Inf(1))
+	default:
+		report.ProfitFactor = math.NaN()
+	}
+	report.Expectancy = (grossProfit + grossLoss) / n
+
+	sort.Slice(holdings, func(i, j int) bool { return holdings[i] < holdings[j] })
+
+	report.AvgHolding = total / time.Duration(len(trades))
+	report.MinHolding = holdings[0]
+	report.MaxHolding = holdings[len(holdings)-1]
+	report.MedianHolding = holdings[len(holdings)/2]
+	if len(holdings)%2 == 0 {
+		report.MedianHolding = (holdings[len(holdings)/2-1] + holdings[len(holdings)/2]) / 2
+	}
+
+	if ohlcv.Len() > 0 {
+		report.Excursions = Excursions(trades, ohlcv)
+	}
+
+	return report
+}
+
+// Excursions returns MAE and MFE of each trade computed from highs and lows of bars
+// with time within [EntryTime, ExitTime]. Excursions are NaN when there are no such bars.
+func Excursions(trades []Trade, ohlcv fta.OHLCV) []Excursion {
+	var (
+		index = ohlcv.Close.Index()
+		highs = ohlcv.High.Values()
+		lows  = ohlcv.Low.Values()
+
+		excursions = make([]Excursion, len(trades))
+	)
+
+	for i, t := range trades {
+		begin := sort.Search(len(index), func(j int) bool { return index[j] >= t.EntryTime })
+		end := sort.Search(len(index), func(j int) bool { return index[j] > t.ExitTime })
+
+		if begin >= end {
+			excursions[i] = Excursion{MAE: math.NaN(), MFE: math.NaN()}
+			continue
+		}
+
+		var (
+			highest = highs[begin]
+			lowest  = lows[begin]
+		)
+
+		for j := begin + 1; j < end; j++ {
+			highest = math.Max(highest, highs[j])
+			lowest = math.Min(lowest, lows[j])
+		}
+
+		var adverse, favorable fta.DType
+
+		if t.Side == Short {
+			adverse, favorable = highest-t.EntryPrice, t.EntryPrice-lowest
+		} else {
+			adverse, favorable = t.EntryPrice-lowest, highest-t.EntryPrice
+		}
+
+		excursions[i] = Excursion{
+			MAE: math.Max(adverse, 0),
+			MFE: math.Max(favorable, 0),
+		}
+	}
+
+	return excursions
+}
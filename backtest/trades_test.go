@@ -0,0 +1,110 @@
+package backtest
+
+import (
+	stdmath "math"
+	"testing"
+	"time"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+)
+
+func trade(entry, exit fta.DType, hours int) Trade {
+	return Trade{
+		Side:       Long,
+		EntryTime:  0,
+		ExitTime:   int64(time.Duration(hours) * time.Hour),
+		EntryPrice: entry,
+		ExitPrice:  exit,
+		Size:       1,
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	trades := []Trade{
+		trade(100, 110, 1),
+		trade(100, 105, 2),
+		trade(100, 95, 3),
+		{Side: Short, EntryPrice: 100, ExitPrice: 90, Size: 2, ExitTime: int64(4 * time.Hour)},
+		trade(100, 100, 5),
+	}
+
+	r := Analyze(trades, fta.OHLCV{})
+
+	if r.Trades != 5 || r.Wins != 3 || r.Losses != 1 {
+		t.Fatalf("got %d trades, %d wins, %d losses", r.Trades, r.Wins, r.Losses)
+	}
+
+	checks := []struct {
+		name      string
+		got, want fta.DType
+	}{
+		{"WinRate", r.WinRate, 0.6},
+		{"AvgWin", r.AvgWin, 35.0 / 3},
+		{"AvgLoss", r.AvgLoss, -5},
+		{"ProfitFactor", r.ProfitFactor, 7},
+		{"Expectancy", r.Expectancy, 6},
+	}
+
+	for _, c := range checks {
+		if stdmath.Abs(float64(c.got-c.want)) > 1e-4 {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	if r.MaxConsecutiveWins != 2 || r.MaxConsecutiveLosses != 1 {
+		t.Errorf("got streaks %d/%d, want 2/1", r.MaxConsecutiveWins, r.MaxConsecutiveLosses)
+	}
+	if r.MinHolding != time.Hour || r.MaxHolding != 5*time.Hour || r.MedianHolding != 3*time.Hour || r.AvgHolding != 3*time.Hour {
+		t.Errorf("got holdings min %v max %v median %v avg %v", r.MinHolding, r.MaxHolding, r.MedianHolding, r.AvgHolding)
+	}
+}
+
+func TestAnalyzeProfitFactor(t *testing.T) {
+	tests := []struct {
+		name   string
+		trades []Trade
+		want   fta.DType
+	}{
+		{"all wins", []Trade{trade(100, 110, 1), trade(100, 120, 1)}, fta.DType(stdmath.Inf(1))},
+		{"all losses", []Trade{trade(100, 90, 1), trade(100, 80, 1)}, 0},
+		{"break even", []Trade{trade(100, 100, 1)}, fta.DType(stdmath.NaN())},
+		{"empty", nil, fta.DType(stdmath.NaN())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyze(tt.trades, fta.OHLCV{}).ProfitFactor
+			if got != tt.want && !(series.IsNA(got) && series.IsNA(tt.want)) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcursions(t *testing.T) {
+	hour := int64(time.Hour)
+	index := []int64{0, hour, 2 * hour, 3 * hour}
+
+	ohlcv := fta.OHLCV{
+		Open:   series.MakeData(hour, index, []fta.DType{100, 100, 100, 100}),
+		High:   series.MakeData(hour, index, []fta.DType{101, 108, 103, 120}),
+		Low:    series.MakeData(hour, index, []fta.DType{99, 96, 98, 90}),
+		Close:  series.MakeData(hour, index, []fta.DType{100, 100, 100, 100}),
+		Volume: series.MakeData(hour, index, []fta.DType{1, 1, 1, 1}),
+	}
+
+	trades := []Trade{
+		{Side: Long, EntryTime: 0, ExitTime: 2 * hour, EntryPrice: 100, ExitPrice: 102, Size: 1},
+		{Side: Short, EntryTime: hour, ExitTime: 2 * hour, EntryPrice: 100, ExitPrice: 99, Size: 1},
+	}
+
+	got := Excursions(trades, ohlcv)
+	want := []Excursion{{MAE: 4, MFE: 8}, {MAE: 8, MFE: 4}}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("trade %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// AdvanceDecline counts symbols of the panel which closed higher (advances), lower (declines)
+// or at the same price (unchanged) than at their previous bar.
+// The result is indexed by the union of timestamps of all symbols,
+// symbols without a bar or with NaN close at the timestamp aren't counted.
+func AdvanceDecline(panel Panel) (advances, declines, unchanged series.Data) {
+	var (
+		index, pos = breadthIndex(panel)
+		adv        = make([]DType, len(index))
+		dec        = make([]DType, len(index))
+		unch       = make([]DType, len(index))
+	)
+
+	for _, symbol := range panel.Symbols() {
+		var (
+			close = panel[symbol].Close
+			ts    = close.Index()
+		)
+
+		for i, v := range close.Values() {
+			if i == 0 {
+				continue
+			}
+
+			prev := close.Values()[i-1]
+			if series.IsNA(v) || series.IsNA(prev) {
+				continue
+			}
+
+			j := pos[ts[i]]
+
+			switch {
+			case v > prev:
+				adv[j]++
+			case v < prev:
+				dec[j]++
+			default:
+				unch[j]++
+			}
+		}
+	}
+
+	freq := breadthFreq(panel)
+
+	advances = series.MakeData(freq, index, adv)
+	declines = series.MakeData(freq, append([]int64(nil), index...), dec)
+	unchanged = series.MakeData(freq, append([]int64(nil), index...), unch)
+
+	return advances, declines, unchanged
+}
+
+// AdvanceDeclineLine is the cumulative sum of net advances, the number of advances minus the number of declines.
+// The line rising together with the index confirms the trend, divergences warn of its weakness.
+func AdvanceDeclineLine(panel Panel) (adLine series.Data) {
+	adLine = netAdvances(panel)
+
+	var sum DType
+	for i, v := range adLine.Values() {
+		sum += v
+		adLine.Values()[i] = sum
+	}
+
+	return adLine
+}
+
+// AdvanceDeclineRatio is the number of advances divided by the number of declines.
+// Division by zero follows DivByZero policy.
+func AdvanceDeclineRatio(panel Panel) (ratio series.Data) {
+	advances, declines, _ := AdvanceDecline(panel)
+	ratio = div(advances, declines)
+	return ratio
+}
+
+// PercentAboveMA is the percent of symbols which close above their simple moving average of the period.
+// Symbols are counted only at timestamps where their average is defined.
+// Values are NaN when there are no such symbols.
+func PercentAboveMA(panel Panel, period int) (percent series.Data) {
+	var (
+		index, pos = breadthIndex(panel)
+		above      = make([]DType, len(index))
+		total      = make([]DType, len(index))
+	)
+
+	for _, symbol := range panel.Symbols() {
+		var (
+			close = panel[symbol].Close
+			ma    = SMA(close, period).Values()
+			ts    = close.Index()
+		)
+
+		for i, v := range close.Values() {
+			if series.IsNA(v) || series.IsNA(ma[i]) {
+				continue
+			}
+
+			j := pos[ts[i]]
+
+			total[j]++
+			if v > ma[i] {
+				above[j]++
+			}
+		}
+	}
+
+	for i, n := range total {
+		if n == 0 {
+			above[i] = math.NaN()
+			continue
+		}
+		above[i] = 100 * above[i] / n
+	}
+
+	percent = series.MakeData(breadthFreq(panel), index, above)
+
+	return percent
+}
+
+// McClellan returns McClellan Oscillator and Summation Index of the panel.
+// The oscillator is the difference of fast and slow EMAs of net advances, classic periods are 19 and 39.
+// The summation index is the cumulative sum of the oscillator.
+func McClellan(panel Panel, periodFast, periodSlow int) (oscillator, summation series.Data) {
+	var (
+		net  = netAdvances(panel)
+		fast = EMA(net, periodFast, false)
+		slow = EMA(net, periodSlow, false)
+	)
+
+	oscillator = fast.Sub(slow)
+	summation = oscillator.Clone()
+
+	var sum DType
+	for i, v := range summation.Values() {
+		sum += v
+		summation.Values()[i] = sum
+	}
+
+	return oscillator, summation
+}
+
+// netAdvances returns the number of advances minus the number of declines.
+func netAdvances(panel Panel) series.Data {
+	advances, declines, _ := AdvanceDecline(panel)
+	return advances.Sub(declines)
+}
+
+// breadthIndex returns the union of timestamps of all symbols and positions of timestamps in it.
+func breadthIndex(panel Panel) (index []int64, pos map[int64]int) {
+	for _, symbol := range panel.Symbols() {
+		index = unionIndex(index, panel[symbol].Close.Index())
+	}
+
+	pos = make(map[int64]int, len(index))
+	for i, ts := range index {
+		pos[ts] = i
+	}
+
+	return index, pos
+}
+
+// breadthFreq returns frequency of the first symbol, zero for the empty panel.
+func breadthFreq(panel Panel) int64 {
+	if symbols := panel.Symbols(); len(symbols) > 0 {
+		return panel[symbols[0]].Close.Freq()
+	}
+	return 0
+}
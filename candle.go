@@ -0,0 +1,154 @@
+package fta
+
+import (
+	"time"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// Candle is a row representation of the ohlcv bar.
+type Candle struct {
+	Time          time.Time
+	O, H, L, C, V float64
+}
+
+// FromCandles makes ohlcv frame from candles.
+// Candles must be sorted by time. The frequency is detected as the most frequent interval between candles.
+func FromCandles(candles []Candle) OHLCV {
+	builder := newOHLCVBuilder(len(candles))
+
+	for _, c := range candles {
+		builder.append(Bar{
+			Time:   c.Time.UnixNano(),
+			Open:   DType(c.O),
+			High:   DType(c.H),
+			Low:    DType(c.L),
+			Close:  DType(c.C),
+			Volume: DType(c.V),
+		})
+	}
+
+	return builder.build(detectFreq(builder.t))
+}
+
+// Candles returns rows of ohlcv as candles. Time of candles is in UTC.
+func (ohlcv OHLCV) Candles() []Candle {
+	var (
+		index = ohlcv.Close.Index()
+
+		o = ohlcv.Open.Values()
+		h = ohlcv.High.Values()
+		l = ohlcv.Low.Values()
+		c = ohlcv.Close.Values()
+		v = ohlcv.Volume.Values()
+	)
+
+	candles := make([]Candle, len(index))
+	for i, ts := range index {
+		candles[i] = Candle{
+			Time: time.Unix(0, ts).UTC(),
+			O:    float64(o[i]),
+			H:    float64(h[i]),
+			L:    float64(l[i]),
+			C:    float64(c[i]),
+			V:    float64(v[i]),
+		}
+	}
+
+	return candles
+}
+
+// CandleStats are per-bar features of candle anatomy.
+// Ratios to the range follow DivByZero policy for bars with equal high and low.
+type CandleStats struct {
+	// Gap is the change of the open from the previous close in percents.
+	Gap series.Data
+	// Body is the ratio of the absolute difference of close and open to the range.
+	Body series.Data
+	// UpperWick is the ratio of the distance from the body top to the high to the range.
+	UpperWick series.Data
+	// LowerWick is the ratio of the distance from the low to the body bottom to the range.
+	LowerWick series.Data
+	// RangeExpansion is the ratio of the range to the range of the previous bar.
+	// Values above 1 mark wide range bars, values below 1 mark narrow range bars.
+	RangeExpansion series.Data
+}
+
+// BarStats computes candle anatomy features of ohlcv bars.
+// The first values of Gap and RangeExpansion are NaN.
+func BarStats(ohlcv OHLCV) (stats CandleStats) {
+	var (
+		o = ohlcv.Open.Values()
+		h = ohlcv.High.Values()
+		l = ohlcv.Low.Values()
+		c = ohlcv.Close.Values()
+	)
+
+	var (
+		gap   = ohlcv.Close.Clone()
+		body  = ohlcv.Close.Clone()
+		upper = ohlcv.Close.Clone()
+		lower = ohlcv.Close.Clone()
+		rng   = ohlcv.Close.Clone()
+	)
+
+	g, b, u, lw, r := gap.Values(), body.Values(), upper.Values(), lower.Values(), rng.Values()
+
+	for i := range c {
+		var (
+			top    = math.Max(o[i], c[i])
+			bottom = math.Min(o[i], c[i])
+			width  = h[i] - l[i]
+		)
+
+		b[i] = divide(math.Abs(c[i]-o[i]), width)
+		u[i] = divide(h[i]-top, width)
+		lw[i] = divide(bottom-l[i], width)
+
+		if i == 0 {
+			g[i] = math.NaN()
+			r[i] = math.NaN()
+			continue
+		}
+
+		g[i] = divide(o[i]-c[i-1], c[i-1]) * 100
+		r[i] = divide(width, h[i-1]-l[i-1])
+	}
+
+	stats = CandleStats{
+		Gap:            gap,
+		Body:           body,
+		UpperWick:      upper,
+		LowerWick:      lower,
+		RangeExpansion: rng,
+	}
+
+	return stats
+}
+
+// detectFreq returns the most frequent positive interval between neighbour timestamps of sorted index.
+// Ties are resolved in favor of the smaller interval. Returns 0 if the frequency can't be detected.
+func detectFreq(index []int64) int64 {
+	counts := map[int64]int{}
+
+	for i := 1; i < len(index); i++ {
+		if d := index[i] - index[i-1]; d > 0 {
+			counts[d]++
+		}
+	}
+
+	var (
+		freq int64
+		best int
+	)
+
+	for d, n := range counts {
+		if n > best || (n == best && d < freq) {
+			freq = d
+			best = n
+		}
+	}
+
+	return freq
+}
@@ -0,0 +1,42 @@
+package fta
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCandles(t *testing.T) {
+	start := time.Date(2023, 1, 2, 3, 0, 0, 0, time.FixedZone("", 3*3600))
+
+	// One gap doesn't change the detected frequency.
+	candles := []Candle{
+		{Time: start, O: 1, H: 3, L: 0.5, C: 2, V: 10},
+		{Time: start.Add(time.Minute), O: 2, H: 4, L: 1.5, C: 3, V: 11},
+		{Time: start.Add(2 * time.Minute), O: 3, H: 5, L: 2.5, C: 4, V: 12},
+		{Time: start.Add(5 * time.Minute), O: 4, H: 6, L: 3.5, C: 5, V: 13},
+	}
+
+	ohlcv := FromCandles(candles)
+
+	if freq := ohlcv.Close.Freq(); freq != int64(time.Minute) {
+		t.Fatalf("got freq %v, want 1m", time.Duration(freq))
+	}
+
+	got := ohlcv.Candles()
+	if len(got) != len(candles) {
+		t.Fatalf("got %d candles, want %d", len(got), len(candles))
+	}
+	for i, c := range candles {
+		if !got[i].Time.Equal(c.Time) || got[i].Time.Location() != time.UTC {
+			t.Fatalf("candle %d: got time %v, want %v in UTC", i, got[i].Time, c.Time)
+		}
+		got[i].Time = c.Time
+		if got[i] != c {
+			t.Fatalf("candle %d: got %+v, want %+v", i, got[i], c)
+		}
+	}
+
+	if ohlcv = FromCandles(nil); ohlcv.Len() != 0 || len(ohlcv.Candles()) != 0 {
+		t.Fatalf("got %d bars of no candles", ohlcv.Len())
+	}
+}
@@ -0,0 +1,352 @@
+package fta
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnixTime is the unit of timestamps.
+type UnixTime int
+
+const (
+	Seconds UnixTime = iota
+	Milliseconds
+	Microseconds
+	Nanoseconds
+	// AutoTime detects the unit of epoch timestamps by their magnitude
+	// and also accepts RFC3339/ISO 8601 strings and fractional seconds.
+	AutoTime
+)
+
+// autoTimeLayouts are layouts tried by AutoTime for non-numeric timestamps.
+var autoTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// CSVColumns are positions of ohlcv fields in csv record.
+type CSVColumns struct{ Time, Open, High, Low, Close, Volume int }
+
+// DefaultCSVColumns is the columns order: Time Open High Low Close Volume.
+var DefaultCSVColumns = CSVColumns{Time: 0, Open: 1, High: 2, Low: 3, Close: 4, Volume: 5}
+
+// CSVNames are names of ohlcv fields in csv header.
+type CSVNames struct{ Time, Open, High, Low, Close, Volume string }
+
+// CSVOptions configures csv parsing.
+type CSVOptions struct {
+	// Freq is a sample size, usually it's time.Second or time.Millisecond.
+	Freq int64
+	// UnixTime is the unit of timestamps.
+	UnixTime UnixTime
+	// TimeLayout is the layout of timestamps in format of time.Parse, e.g. time.RFC3339.
+	// Timestamps without time zone are parsed as UTC. Overrides UnixTime.
+	TimeLayout string
+	// Header means that the first record is the header.
+	// The header is skipped when Names are not set.
+	Header bool
+	// Names maps header names to ohlcv fields. Names are case insensitive.
+	// All fields must be present in the header. Requires Header.
+	Names CSVNames
+	// Columns are positions of ohlcv fields.
+	// Used when Names are not set, zero value means DefaultCSVColumns.
+	Columns CSVColumns
+	// Comma is the field delimiter used by ReadCSVFile, zero value means ','.
+	Comma rune
+	// SizeHint is the expected number of bars.
+	// It's used to preallocate columns buffers.
+	SizeHint int
+	// Progress receives progress of ReadCSVFile, nil disables reporting.
+	Progress ProgressFunc
+}
+
+// ReadCSV parses ohlcv from csv reader.
+// The columns are read at this order: Time Open High Low Close Volume.
+// freq is a sample size, usually it's time.Second or time.Millisecond.
+func ReadCSV(reader *csv.Reader, freq int64, unixTime UnixTime) (ohlcv OHLCV, err error) {
+	return ReadCSVWithOptions(reader, CSVOptions{
+		Freq:     freq,
+		UnixTime: unixTime,
+	})
+}
+
+// ReadCSVWithOptions parses ohlcv from csv reader configured by opts.
+// Columns which are not mapped to ohlcv fields are ignored.
+func ReadCSVWithOptions(reader *csv.Reader, opts CSVOptions) (ohlcv OHLCV, err error) {
+	builder := newOHLCVBuilder(opts.SizeHint)
+
+	err = ScanCSV(reader, opts, func(bar Bar) error {
+		builder.append(bar)
+		return nil
+	})
+	if err != nil {
+		return ohlcv, err
+	}
+
+	ohlcv = builder.build(opts.Freq)
+
+	return ohlcv, nil
+}
+
+// ScanCSV parses csv records one by one and passes bars to fn, so the data is never kept in memory as a whole.
+// Enabling ReuseRecord of the reader reduces allocations.
+// Scanning stops at the first error returned by fn, the error is returned as is.
+func ScanCSV(reader *csv.Reader, opts CSVOptions, fn func(bar Bar) error) error {
+	columns, err := opts.readHeader(reader)
+	if err != nil {
+		return err
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("read csv: %w", err)
+		}
+
+		bar, err := opts.ParseRecord(record, columns)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(bar); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParseRecord parses the bar from csv record by columns positions.
+func (opts CSVOptions) ParseRecord(record []string, columns CSVColumns) (bar Bar, err error) {
+	if len(record) < columns.width() {
+		return bar, fmt.Errorf("parse record: record has %d fields, want at least %d", len(record), columns.width())
+	}
+
+	ts, err := opts.ParseTime(record[columns.Time])
+	if err != nil {
+		return bar, err
+	}
+
+	o, err := strconv.ParseFloat(record[columns.Open], 64)
+	if err != nil {
+		return bar, fmt.Errorf("parse float: field 'Open': %w", err)
+	}
+
+	h, err := strconv.ParseFloat(record[columns.High], 64)
+	if err != nil {
+		return bar, fmt.Errorf("parse float: field 'High': %w", err)
+	}
+
+	l, err := strconv.ParseFloat(record[columns.Low], 64)
+	if err != nil {
+		return bar, fmt.Errorf("parse float: field 'Low': %w", err)
+	}
+
+	c, err := strconv.ParseFloat(record[columns.Close], 64)
+	if err != nil {
+		return bar, fmt.Errorf("parse float: field 'Close': %w", err)
+	}
+
+	v, err := strconv.ParseFloat(record[columns.Volume], 64)
+	if err != nil {
+		return bar, fmt.Errorf("parse float: field 'Volume': %w", err)
+	}
+
+	bar = Bar{
+		Time:   ts,
+		Open:   DType(o),
+		High:   DType(h),
+		Low:    DType(l),
+		Close:  DType(c),
+		Volume: DType(v),
+	}
+
+	return bar, nil
+}
+
+// readHeader reads the header if required and resolves columns positions.
+func (opts CSVOptions) readHeader(reader *csv.Reader) (columns CSVColumns, err error) {
+	columns = opts.Columns
+	if columns == (CSVColumns{}) {
+		columns = DefaultCSVColumns
+	}
+
+	if !opts.Header {
+		if opts.Names != (CSVNames{}) {
+			return columns, errors.New("csv names require header")
+		}
+		return columns, nil
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return columns, fmt.Errorf("read csv header: %w", err)
+	}
+
+	if opts.Names != (CSVNames{}) {
+		return opts.Names.resolve(header)
+	}
+
+	return columns, nil
+}
+
+// ParseTime parses timestamp to nanoseconds since epoch.
+func (opts CSVOptions) ParseTime(field string) (int64, error) {
+	if opts.TimeLayout != "" {
+		t, err := time.Parse(opts.TimeLayout, field)
+		if err != nil {
+			return 0, fmt.Errorf("parse time: field 'Time': %w", err)
+		}
+		return t.UnixNano(), nil
+	}
+
+	ts, err := strconv.ParseInt(field, 10, 64)
+	if err != nil {
+		if opts.UnixTime == AutoTime {
+			return parseAutoTime(field)
+		}
+		return 0, fmt.Errorf("parse int: field 'Time': %w", err)
+	}
+
+	unit := opts.UnixTime
+	if unit == AutoTime {
+		unit = detectUnixTime(ts)
+	}
+
+	switch unit {
+	case Seconds:
+		ts *= int64(time.Second)
+	case Milliseconds:
+		ts *= int64(time.Millisecond)
+	case Microseconds:
+		ts *= int64(time.Microsecond)
+	}
+
+	return ts, nil
+}
+
+// detectUnixTime guesses the unit of epoch timestamp by its magnitude.
+// Seconds are assumed up to year 5138, smaller units are tried the same way.
+func detectUnixTime(ts int64) UnixTime {
+	if ts < 0 {
+		ts = -ts
+	}
+	switch {
+	case ts < 1e11:
+		return Seconds
+	case ts < 1e14:
+		return Milliseconds
+	case ts < 1e17:
+		return Microseconds
+	default:
+		return Nanoseconds
+	}
+}
+
+// parseAutoTime parses fractional epoch seconds or timestamp of one of the known layouts.
+func parseAutoTime(field string) (int64, error) {
+	if ts, ok := parseFracSeconds(field); ok {
+		return ts, nil
+	}
+	if sec, err := strconv.ParseFloat(field, 64); err == nil {
+		return int64(sec * float64(time.Second)), nil
+	}
+
+	for _, layout := range autoTimeLayouts {
+		if t, err := time.Parse(layout, field); err == nil {
+			return t.UnixNano(), nil
+		}
+	}
+
+	return 0, fmt.Errorf("parse time: field 'Time': unknown format of %q", field)
+}
+
+// parseFracSeconds parses decimal epoch seconds without the float rounding, digits beyond nanoseconds are dropped.
+func parseFracSeconds(field string) (int64, bool) {
+	dot := strings.IndexByte(field, '.')
+	if dot <= 0 || dot == len(field)-1 {
+		return 0, false
+	}
+	whole, frac := field[:dot], field[dot+1:]
+
+	sec, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	var nsec int64
+	for i, c := range frac {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		if i < 9 {
+			nsec = nsec*10 + int64(c-'0')
+		}
+	}
+	for i := len(frac); i < 9; i++ {
+		nsec *= 10
+	}
+
+	if strings.HasPrefix(whole, "-") {
+		nsec = -nsec
+	}
+
+	return sec*int64(time.Second) + nsec, true
+}
+
+// width returns the minimal length of record containing all columns.
+func (columns CSVColumns) width() int {
+	width := 0
+	for _, pos := range []int{columns.Time, columns.Open, columns.High, columns.Low, columns.Close, columns.Volume} {
+		if pos+1 > width {
+			width = pos + 1
+		}
+	}
+	return width
+}
+
+// resolve finds positions of named columns in the header.
+func (names CSVNames) resolve(header []string) (columns CSVColumns, err error) {
+	find := func(field, name string) (int, error) {
+		for i, h := range header {
+			h = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+			if strings.EqualFold(h, name) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("csv header: column %q of field '%s' not found", name, field)
+	}
+
+	fields := []struct {
+		field string
+		name  string
+		pos   *int
+	}{
+		{"Time", names.Time, &columns.Time},
+		{"Open", names.Open, &columns.Open},
+		{"High", names.High, &columns.High},
+		{"Low", names.Low, &columns.Low},
+		{"Close", names.Close, &columns.Close},
+		{"Volume", names.Volume, &columns.Volume},
+	}
+
+	for _, f := range fields {
+		if *f.pos, err = find(f.field, f.name); err != nil {
+			return columns, err
+		}
+	}
+
+	return columns, nil
+}
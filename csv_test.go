@@ -0,0 +1,225 @@
+package fta
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readCSV parses csv text with options.
+func readCSV(t *testing.T, text string, opts CSVOptions) OHLCV {
+	t.Helper()
+
+	ohlcv, err := ReadCSVWithOptions(csv.NewReader(strings.NewReader(text)), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ohlcv
+}
+
+// assertBars fails the test when bars of ohlcv differ from want.
+func assertBars(t *testing.T, ohlcv OHLCV, want []Bar) {
+	t.Helper()
+
+	if ohlcv.Len() != len(want) {
+		t.Fatalf("got %d bars, want %d", ohlcv.Len(), len(want))
+	}
+	for i, w := range want {
+		if got := ohlcv.Bar(i); got != w {
+			t.Fatalf("bar %d: got %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestReadCSVNames(t *testing.T) {
+	const text = "\ufeffTimestamp,o,h,l,c,vol,quote_vol,trades\n" +
+		"60,1,3,0.5,2,10,20,5\n" +
+		"120,2,4,1.5,3,11,33,6\n"
+
+	ohlcv := readCSV(t, text, CSVOptions{
+		Freq:   int64(time.Minute),
+		Header: true,
+		Names:  CSVNames{Time: "timestamp", Open: "O", High: "H", Low: "L", Close: "C", Volume: "Vol"},
+	})
+
+	assertBars(t, ohlcv, []Bar{
+		{Time: int64(time.Minute), Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 10},
+		{Time: int64(2 * time.Minute), Open: 2, High: 4, Low: 1.5, Close: 3, Volume: 11},
+	})
+}
+
+func TestReadCSVColumns(t *testing.T) {
+	// Columns of the reversed order with the extra column, the header is skipped.
+	const text = "volume,close,low,high,open,time,extra\n" +
+		"10,2,0.5,3,1,60,x\n"
+
+	ohlcv := readCSV(t, text, CSVOptions{
+		Header:  true,
+		Columns: CSVColumns{Volume: 0, Close: 1, Low: 2, High: 3, Open: 4, Time: 5},
+	})
+
+	assertBars(t, ohlcv, []Bar{{Time: int64(time.Minute), Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 10}})
+
+	ohlcv = readCSV(t, "60,1,3,0.5,2,10\n", CSVOptions{})
+	assertBars(t, ohlcv, []Bar{{Time: int64(time.Minute), Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 10}})
+}
+
+func TestReadCSVHeaderErrors(t *testing.T) {
+	names := CSVNames{Time: "time", Open: "open", High: "high", Low: "low", Close: "close", Volume: "volume"}
+
+	tests := []struct {
+		name string
+		text string
+		opts CSVOptions
+		err  string
+	}{
+		{"missing column", "time,open,high,low,close\n", CSVOptions{Header: true, Names: names}, `"volume"`},
+		{"names without header", "60,1,3,0.5,2,10\n", CSVOptions{Names: names}, "require header"},
+		{"short record", "60,1,3\n", CSVOptions{}, "want at least 6"},
+		{"bad number", "60,1,x,0.5,2,10\n", CSVOptions{}, "'High'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCSVWithOptions(csv.NewReader(strings.NewReader(tt.text)), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("got error %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	var (
+		ts    = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+		frac  = ts.Add(250 * time.Millisecond)
+		local = time.Date(2023, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3*3600))
+	)
+
+	tests := []struct {
+		name  string
+		field string
+		opts  CSVOptions
+		want  time.Time
+	}{
+		{"seconds", "1672628645", CSVOptions{UnixTime: Seconds}, ts},
+		{"milliseconds", "1672628645250", CSVOptions{UnixTime: Milliseconds}, frac},
+		{"microseconds", "1672628645250000", CSVOptions{UnixTime: Microseconds}, frac},
+		{"nanoseconds", "1672628645250000000", CSVOptions{UnixTime: Nanoseconds}, frac},
+		{"auto seconds", "1672628645", CSVOptions{UnixTime: AutoTime}, ts},
+		{"auto milliseconds", "1672628645250", CSVOptions{UnixTime: AutoTime}, frac},
+		{"auto microseconds", "1672628645250000", CSVOptions{UnixTime: AutoTime}, frac},
+		{"auto nanoseconds", "1672628645250000000", CSVOptions{UnixTime: AutoTime}, frac},
+		{"auto fractional seconds", "1672628645.25", CSVOptions{UnixTime: AutoTime}, frac},
+		{"auto negative fractional seconds", "-1.5", CSVOptions{UnixTime: AutoTime}, time.Unix(-2, int64(500*time.Millisecond))},
+		{"auto exponent seconds", "1.6726286452e9", CSVOptions{UnixTime: AutoTime}, ts.Add(200 * time.Millisecond)},
+		{"auto rfc3339", "2023-01-02T03:04:05.25Z", CSVOptions{UnixTime: AutoTime}, frac},
+		{"auto rfc3339 offset", "2023-01-02T03:04:05+03:00", CSVOptions{UnixTime: AutoTime}, local},
+		{"auto iso without zone", "2023-01-02 03:04:05", CSVOptions{UnixTime: AutoTime}, ts},
+		{"auto date", "2023-01-02", CSVOptions{UnixTime: AutoTime}, time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"layout", "02.01.2023 03:04:05", CSVOptions{TimeLayout: "02.01.2006 15:04:05"}, ts},
+		{"layout overrides unit", "2023-01-02T03:04:05Z", CSVOptions{TimeLayout: time.RFC3339, UnixTime: Milliseconds}, ts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.opts.ParseTime(tt.field)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want.UnixNano() {
+				t.Fatalf("got %v, want %v", time.Unix(0, got).UTC(), tt.want.UTC())
+			}
+		})
+	}
+}
+
+func TestParseTimeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		opts  CSVOptions
+	}{
+		{"not a number", "2023-01-02", CSVOptions{UnixTime: Milliseconds}},
+		{"unknown format", "yesterday", CSVOptions{UnixTime: AutoTime}},
+		{"layout mismatch", "2023-01-02", CSVOptions{TimeLayout: time.RFC3339}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.opts.ParseTime(tt.field); err == nil {
+				t.Fatal("got no error")
+			}
+		})
+	}
+}
+
+func TestReadCSVAutoTime(t *testing.T) {
+	// Binance exports are ms epochs.
+	ohlcv := readCSV(t, "1672628640000,1,3,0.5,2,10\n1672628700000,2,4,1.5,3,11\n", CSVOptions{
+		Freq:     int64(time.Minute),
+		UnixTime: AutoTime,
+	})
+
+	start := time.Date(2023, 1, 2, 3, 4, 0, 0, time.UTC).UnixNano()
+
+	assertBars(t, ohlcv, []Bar{
+		{Time: start, Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 10},
+		{Time: start + int64(time.Minute), Open: 2, High: 4, Low: 1.5, Close: 3, Volume: 11},
+	})
+}
+
+func TestScanCSV(t *testing.T) {
+	var text strings.Builder
+	want := make([]Bar, 1000)
+	for i := range want {
+		v := DType(i)
+		want[i] = Bar{Time: int64(i+1) * int64(time.Minute), Open: v, High: v + 2, Low: v - 1, Close: v + 1, Volume: 10}
+		fmt.Fprintf(&text, "%d,%v,%v,%v,%v,10\n", (i+1)*60, v, v+2, v-1, v+1)
+	}
+
+	var got []Bar
+	err := ScanCSV(csv.NewReader(strings.NewReader(text.String())), CSVOptions{}, func(bar Bar) error {
+		got = append(got, bar)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d bars, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("bar %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Preallocated and growable buffers give the same frame.
+	for _, hint := range []int{0, 10, len(want), 2 * len(want)} {
+		ohlcv := readCSV(t, text.String(), CSVOptions{SizeHint: hint})
+		assertBars(t, ohlcv, want)
+	}
+}
+
+func TestScanCSVStop(t *testing.T) {
+	errStop := errors.New("stop")
+
+	var n int
+	err := ScanCSV(csv.NewReader(strings.NewReader("60,1,1,1,1,1\n120,2,2,2,2,2\n180,x,3,3,3,3\n")), CSVOptions{}, func(bar Bar) error {
+		n++
+		if bar.Time == int64(2*time.Minute) {
+			return errStop
+		}
+		return nil
+	})
+
+	// The consumer error stops scanning before the broken record.
+	if !errors.Is(err, errStop) || n != 2 {
+		t.Fatalf("got error %v after %d bars, want stop after 2", err, n)
+	}
+}
@@ -0,0 +1,136 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// Decompose splits the series into trend, seasonal and residual components by the classical additive model:
+// close = trend + seasonal + residual.
+//
+// Trend is the centered moving average over the season period (2 x period moving average for even periods).
+// Seasonal component is the average of detrended values at the same phase of the season, centered around zero.
+// The phase is derived from timestamps, so gaps in the index don't shift the season.
+// Trend and residual are NaNs at the edges where the centered window isn't full.
+// All components are NaNs when the period isn't positive.
+func Decompose(close series.Data, period int) (trend, seasonal, residual series.Data) {
+	if period <= 0 {
+		return allNaN(close), allNaN(close), allNaN(close)
+	}
+
+	var (
+		values = close.Values()
+		phases = seasonPhases(close, period)
+	)
+
+	trend = close.Clone()
+	centeredMean(values, trend.Values(), period)
+
+	var (
+		sums   = make([]DType, period)
+		counts = make([]int, period)
+		tv     = trend.Values()
+	)
+
+	for i, v := range values {
+		d := v - tv[i]
+		if series.IsNA(d) {
+			continue
+		}
+		sums[phases[i]] += d
+		counts[phases[i]]++
+	}
+
+	var (
+		avg    DType
+		filled int
+	)
+
+	for k := range sums {
+		if counts[k] == 0 {
+			sums[k] = math.NaN()
+			continue
+		}
+		sums[k] /= DType(counts[k])
+		avg += sums[k]
+		filled++
+	}
+
+	if filled > 0 {
+		avg /= DType(filled)
+	}
+
+	seasonal = close.Clone()
+	sv := seasonal.Values()
+
+	for i := range sv {
+		sv[i] = sums[phases[i]] - avg
+	}
+
+	residual = close.Clone().Sub(trend).Sub(seasonal)
+
+	return trend, seasonal, residual
+}
+
+// seasonPhases returns position of every sample within the season.
+func seasonPhases(column series.Data, period int) []int {
+	var (
+		phases = make([]int, column.Len())
+		index  = column.Index()
+		freq   = column.Freq()
+	)
+
+	for i := range phases {
+		if freq <= 0 || index == nil {
+			phases[i] = i % period
+			continue
+		}
+
+		phase := (index[i] / freq) % int64(period)
+		if phase < 0 {
+			phase += int64(period)
+		}
+
+		phases[i] = int(phase)
+	}
+
+	return phases
+}
+
+// centeredMean writes centered moving average of src into dst.
+func centeredMean(src, dst []DType, period int) {
+	var (
+		half    = period / 2
+		even    = period%2 == 0
+		weights = make([]DType, 0, period+1)
+	)
+
+	if even {
+		weights = append(weights, 0.5)
+		for i := 1; i < period; i++ {
+			weights = append(weights, 1)
+		}
+		weights = append(weights, 0.5)
+	} else {
+		for i := 0; i < period; i++ {
+			weights = append(weights, 1)
+		}
+	}
+
+	for i := range dst {
+		l := i - half
+		r := l + len(weights)
+
+		if l < 0 || r > len(src) {
+			dst[i] = math.NaN()
+			continue
+		}
+
+		var sum DType
+		for j, w := range weights {
+			sum += src[l+j] * w
+		}
+
+		dst[i] = sum / DType(period)
+	}
+}
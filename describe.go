@@ -0,0 +1,139 @@
+package fta
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// ColumnStats are summary statistics of the column. NaN values are excluded.
+type ColumnStats struct {
+	Count                                 int
+	Mean, Std, Min, Q25, Median, Q75, Max DType
+}
+
+// Description is the summary of ohlcv.
+type Description struct {
+	// Bars is the number of bars.
+	Bars int
+	// Begin and End are times of the first and the last bars in UTC.
+	Begin, End time.Time
+	// Freq is the frequency of the frame.
+	Freq int64
+	// DetectedFreq is the most frequent interval between bars.
+	DetectedFreq int64
+
+	Open, High, Low, Close, Volume ColumnStats
+}
+
+// Describe returns summary statistics of ohlcv like pandas' DataFrame.describe does.
+// Std is the sample standard deviation.
+func (ohlcv OHLCV) Describe() Description {
+	index := ohlcv.Close.Index()
+
+	desc := Description{
+		Bars:         len(index),
+		Freq:         ohlcv.Close.Freq(),
+		DetectedFreq: detectFreq(index),
+		Open:         describeColumn(ohlcv.Open),
+		High:         describeColumn(ohlcv.High),
+		Low:          describeColumn(ohlcv.Low),
+		Close:        describeColumn(ohlcv.Close),
+		Volume:       describeColumn(ohlcv.Volume),
+	}
+
+	if len(index) > 0 {
+		desc.Begin = time.Unix(0, index[0]).UTC()
+		desc.End = time.Unix(0, index[len(index)-1]).UTC()
+	}
+
+	return desc
+}
+
+// String formats the description as a table.
+func (desc Description) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "bars: %d\n", desc.Bars)
+	fmt.Fprintf(&sb, "range: %s - %s\n", desc.Begin.Format(time.RFC3339), desc.End.Format(time.RFC3339))
+	fmt.Fprintf(&sb, "freq: %s (detected %s)\n", time.Duration(desc.Freq), time.Duration(desc.DetectedFreq))
+
+	columns := []struct {
+		name  string
+		stats ColumnStats
+	}{
+		{"open", desc.Open},
+		{"high", desc.High},
+		{"low", desc.Low},
+		{"close", desc.Close},
+		{"volume", desc.Volume},
+	}
+
+	fmt.Fprintf(&sb, "%-7s", "")
+	for _, c := range columns {
+		fmt.Fprintf(&sb, " %14s", c.name)
+	}
+	sb.WriteByte('\n')
+
+	rows := []struct {
+		name  string
+		value func(s ColumnStats) DType
+	}{
+		{"count", func(s ColumnStats) DType { return DType(s.Count) }},
+		{"mean", func(s ColumnStats) DType { return s.Mean }},
+		{"std", func(s ColumnStats) DType { return s.Std }},
+		{"min", func(s ColumnStats) DType { return s.Min }},
+		{"25%", func(s ColumnStats) DType { return s.Q25 }},
+		{"50%", func(s ColumnStats) DType { return s.Median }},
+		{"75%", func(s ColumnStats) DType { return s.Q75 }},
+		{"max", func(s ColumnStats) DType { return s.Max }},
+	}
+
+	for _, row := range rows {
+		fmt.Fprintf(&sb, "%-7s", row.name)
+		for _, c := range columns {
+			fmt.Fprintf(&sb, " %14.6g", row.value(c.stats))
+		}
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+func describeColumn(column series.Data) (stats ColumnStats) {
+	sorted := sortedNotNA(column.Values())
+
+	stats.Count = len(sorted)
+
+	if stats.Count == 0 {
+		nan := math.NaN()
+		stats.Mean, stats.Std, stats.Min, stats.Q25, stats.Median, stats.Q75, stats.Max = nan, nan, nan, nan, nan, nan, nan
+		return stats
+	}
+
+	var sum DType
+	for _, v := range sorted {
+		sum += v
+	}
+	stats.Mean = sum / DType(stats.Count)
+
+	stats.Std = math.NaN()
+	if stats.Count > 1 {
+		var ss DType
+		for _, v := range sorted {
+			ss += (v - stats.Mean) * (v - stats.Mean)
+		}
+		stats.Std = math.Sqrt(ss / DType(stats.Count-1))
+	}
+
+	stats.Min = sorted[0]
+	stats.Q25 = quantile(sorted, 0.25)
+	stats.Median = quantile(sorted, 0.5)
+	stats.Q75 = quantile(sorted, 0.75)
+	stats.Max = sorted[len(sorted)-1]
+
+	return stats
+}
@@ -0,0 +1,71 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// DivPolicy is the policy of division by zero.
+type DivPolicy int
+
+const (
+	// DivNaN makes the result of division by zero NaN.
+	DivNaN DivPolicy = iota
+	// DivZero makes the result of division by zero zero.
+	DivZero
+	// DivEpsilon replaces zero divisor by DivEps, so the result is large but finite.
+	DivEpsilon
+	// DivIEEE follows IEEE 754: division by zero gives ±Inf or NaN for 0/0.
+	DivIEEE
+)
+
+var (
+	// DivByZero is the policy of division by zero used by indicators.
+	// Flat prices and zero volumes are the usual sources of zero divisors.
+	// It must not be changed concurrently with computations.
+	DivByZero = DivNaN
+
+	// DivEps is the divisor used instead of zero by DivEpsilon policy.
+	DivEps DType = series.Eps
+)
+
+// div divides values of a by values of b in place following DivByZero policy and returns a.
+func div(a, b series.Data) series.Data {
+	var (
+		dividends = a.Values()
+		divisors  = b.Values()
+	)
+
+	for i, d := range divisors {
+		dividends[i] = divide(dividends[i], d)
+	}
+
+	return a
+}
+
+// Divide returns x/y following DivByZero policy, so custom and streaming indicators treat zero divisors
+// like the builtin ones.
+func Divide(x, y DType) DType {
+	return divide(x, y)
+}
+
+// divide returns x/y following DivByZero policy.
+func divide(x, y DType) DType {
+	if y != 0 {
+		return x / y
+	}
+
+	switch DivByZero {
+	case DivZero:
+		if math.IsNaN(x) {
+			return x
+		}
+		return 0
+	case DivEpsilon:
+		return x / DivEps
+	case DivIEEE:
+		return x / y
+	default:
+		return math.NaN()
+	}
+}
@@ -0,0 +1,193 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// DivergenceKind is the kind of divergence between price and oscillator.
+type DivergenceKind int
+
+const (
+	// RegularBullish is a lower low of price with a higher low of the oscillator, a possible reversal up.
+	RegularBullish DivergenceKind = iota
+	// RegularBearish is a higher high of price with a lower high of the oscillator, a possible reversal down.
+	RegularBearish
+	// HiddenBullish is a higher low of price with a lower low of the oscillator, a possible continuation of uptrend.
+	HiddenBullish
+	// HiddenBearish is a lower high of price with a higher high of the oscillator, a possible continuation of downtrend.
+	HiddenBearish
+)
+
+// DivergenceOptions are parameters of divergence detection.
+type DivergenceOptions struct {
+	// Left and Right are the numbers of bars on the left and on the right side of pivots of price, default is 5.
+	// Pivots are confirmed Right bars later.
+	Left, Right int
+	// MinDistance and MaxDistance limit the number of bars between two pivots, default are 5 and 60.
+	MinDistance, MaxDistance int
+}
+
+func (opts DivergenceOptions) withDefaults() DivergenceOptions {
+	if opts.Left <= 0 {
+		opts.Left = 5
+	}
+	if opts.Right <= 0 {
+		opts.Right = 5
+	}
+	if opts.MinDistance <= 0 {
+		opts.MinDistance = 5
+	}
+	if opts.MaxDistance <= 0 {
+		opts.MaxDistance = 60
+	}
+	return opts
+}
+
+// DivergenceEvent is the divergence between two consecutive pivots of price.
+// Anchor, Pivot and Confirmed are positions of bars.
+type DivergenceEvent struct {
+	Kind DivergenceKind
+	// Anchor is the earlier pivot, Pivot is the later one.
+	Anchor, Pivot int
+	// Confirmed is the bar where Pivot is confirmed and the divergence is known, Pivot plus Right bars.
+	Confirmed int
+}
+
+// Divergences are signals of every kind of divergence. Signals are set at confirmation bars to positions
+// of anchoring pivots, they are NaN at other bars.
+type Divergences struct {
+	RegularBullish, RegularBearish series.Data
+	HiddenBullish, HiddenBearish   series.Data
+	// Events are all divergences ordered by confirmation bars.
+	Events []DivergenceEvent
+}
+
+// Signal returns signals of the kind.
+func (d Divergences) Signal(kind DivergenceKind) series.Data {
+	switch kind {
+	case RegularBullish:
+		return d.RegularBullish
+	case RegularBearish:
+		return d.RegularBearish
+	case HiddenBullish:
+		return d.HiddenBullish
+	case HiddenBearish:
+		return d.HiddenBearish
+	}
+
+	panic("unknown divergence kind")
+}
+
+// Divergence detects regular and hidden divergences between price and the oscillator, e.g. RSI or MACD histogram.
+// Consecutive pivot lows of price are compared to values of the oscillator at the same bars for bullish divergences
+// and pivot highs for bearish ones. Pivots with NaN values of the oscillator don't produce divergences.
+func Divergence(price, oscillator series.Data, opts DivergenceOptions) (divergences Divergences) {
+	opts = opts.withDefaults()
+
+	var (
+		p   = price.Values()
+		osc = oscillator.Values()
+
+		signals = make([]series.Data, 4)
+		values  = make([][]DType, 4)
+
+		lastLow, lastHigh = -1, -1
+	)
+
+	for k := range signals {
+		signals[k] = price.Clone()
+		values[k] = signals[k].Values()
+		for i := range values[k] {
+			values[k][i] = math.NaN()
+		}
+	}
+
+	emit := func(kind DivergenceKind, anchor, pivot int) {
+		confirmed := pivot + opts.Right
+		values[kind][confirmed] = DType(anchor)
+		divergences.Events = append(divergences.Events, DivergenceEvent{
+			Kind:      kind,
+			Anchor:    anchor,
+			Pivot:     pivot,
+			Confirmed: confirmed,
+		})
+	}
+
+	compare := func(anchor, pivot int) (priceDelta, oscDelta DType, ok bool) {
+		if anchor < 0 || series.IsNA(osc[anchor]) || series.IsNA(osc[pivot]) {
+			return 0, 0, false
+		}
+		if d := pivot - anchor; d < opts.MinDistance || d > opts.MaxDistance {
+			return 0, 0, false
+		}
+		return p[pivot] - p[anchor], osc[pivot] - osc[anchor], true
+	}
+
+	for i := opts.Left; i+opts.Right < len(p); i++ {
+		low, high := pivots(p, i, opts.Left, opts.Right)
+
+		if low {
+			if dp, do, ok := compare(lastLow, i); ok {
+				switch {
+				case dp < 0 && do > 0:
+					emit(RegularBullish, lastLow, i)
+				case dp > 0 && do < 0:
+					emit(HiddenBullish, lastLow, i)
+				}
+			}
+			lastLow = i
+		}
+
+		if high {
+			if dp, do, ok := compare(lastHigh, i); ok {
+				switch {
+				case dp > 0 && do < 0:
+					emit(RegularBearish, lastHigh, i)
+				case dp < 0 && do > 0:
+					emit(HiddenBearish, lastHigh, i)
+				}
+			}
+			lastHigh = i
+		}
+	}
+
+	divergences.RegularBullish = signals[RegularBullish]
+	divergences.RegularBearish = signals[RegularBearish]
+	divergences.HiddenBullish = signals[HiddenBullish]
+	divergences.HiddenBearish = signals[HiddenBearish]
+
+	return divergences
+}
+
+// pivots reports whether the i-th value is a pivot low or high: strictly lower or higher than left values
+// and not higher or lower than right values, so the first bar of a flat extreme is the pivot.
+func pivots(values []DType, i, left, right int) (low, high bool) {
+	v := values[i]
+	if series.IsNA(v) {
+		return false, false
+	}
+
+	low, high = true, true
+
+	for j := i - left; j <= i+right && (low || high); j++ {
+		if j == i {
+			continue
+		}
+
+		u := values[j]
+		if series.IsNA(u) {
+			return false, false
+		}
+
+		if j < i {
+			low = low && v < u
+			high = high && v > u
+		} else {
+			low = low && v <= u
+			high = high && v >= u
+		}
+	}
+
+	return low, high
+}
@@ -0,0 +1,128 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// SuperSmoother is John Ehlers' two-pole Butterworth low-pass filter, smoothing cycles shorter than the period
+// with much less lag than moving averages of the same smoothness.
+func SuperSmoother(column series.Data, period int) (smooth series.Data) {
+	smooth = column.Clone()
+	superSmoother(smooth.Values(), column.Values(), period)
+	return smooth
+}
+
+// HighPass is John Ehlers' two-pole high-pass filter, it removes trends with periods longer than the period.
+func HighPass(column series.Data, period int) (hp series.Data) {
+	var (
+		x     = column.Values()
+		angle = 0.707 * 2 * math.Pi / DType(period)
+		a1    = (math.Cos(angle) + math.Sin(angle) - 1) / math.Cos(angle)
+		k     = (1 - a1/2) * (1 - a1/2)
+	)
+
+	hp = column.Clone()
+	y := hp.Values()
+
+	for i := range y {
+		if i < 2 {
+			y[i] = 0
+			continue
+		}
+		y[i] = k*(x[i]-2*x[i-1]+x[i-2]) + 2*(1-a1)*y[i-1] - (1-a1)*(1-a1)*y[i-2]
+	}
+
+	return hp
+}
+
+// RoofingFilter is John Ehlers' band-pass filter: the high-pass filter removes trends longer than hpPeriod
+// and the super smoother removes noise shorter than ssPeriod. Classic periods are 48 and 10.
+// The result oscillates around zero and keeps only the cycles traders can use.
+func RoofingFilter(close series.Data, hpPeriod, ssPeriod int) (roof series.Data) {
+	roof = SuperSmoother(HighPass(close, hpPeriod), ssPeriod)
+	return roof
+}
+
+// Decycler is John Ehlers' decycler: the price minus its high-pass filter. Cycles shorter than the period are removed
+// and the trend is left almost without lag, unlike moving averages.
+func Decycler(close series.Data, period int) (decycle series.Data) {
+	decycle = close.Clone().Sub(HighPass(close, period))
+	return decycle
+}
+
+// DecyclerOscillator is the decycler of fastPeriod minus the decycler of slowPeriod, it keeps the band of cycles
+// between the periods. Positive values mean the uptrend, crossings of zero are trend reversals. Classic periods are 30 and 60.
+func DecyclerOscillator(close series.Data, fastPeriod, slowPeriod int) (osc series.Data) {
+	osc = Decycler(close, fastPeriod).Sub(Decycler(close, slowPeriod))
+	return osc
+}
+
+// EBSW is John Ehlers' Even Better Sinewave. The roofing filter built from one-pole high-pass filter of the duration
+// and super smoother of ssPeriod is normalized by its power, so the wave swings between -1 and 1.
+// Values stay near the extremes in trends and cross zero at cycle turning points.
+// Classic duration is 40 and ssPeriod is 10.
+func EBSW(close series.Data, duration, ssPeriod int) (wave series.Data) {
+	var (
+		x      = close.Values()
+		angle  = 2 * math.Pi / DType(duration)
+		alpha1 = (1 - math.Sin(angle)) / math.Cos(angle)
+	)
+
+	hp := make([]DType, len(x))
+	for i := 1; i < len(x); i++ {
+		hp[i] = 0.5*(1+alpha1)*(x[i]-x[i-1]) + alpha1*hp[i-1]
+	}
+
+	filt := make([]DType, len(x))
+	superSmoother(filt, hp, ssPeriod)
+
+	wave = close.Clone()
+	w := wave.Values()
+
+	for i := range w {
+		if i < 2 {
+			w[i] = math.NaN()
+			continue
+		}
+
+		var (
+			avg = (filt[i] + filt[i-1] + filt[i-2]) / 3
+			pwr = (filt[i]*filt[i] + filt[i-1]*filt[i-1] + filt[i-2]*filt[i-2]) / 3
+		)
+
+		w[i] = divide(avg, math.Sqrt(pwr))
+	}
+
+	return wave
+}
+
+// superSmoother writes super smoother filter of x to dst, dst and x may be the same slice.
+// The first two values are passed through to initialize the filter.
+func superSmoother(dst, x []DType, period int) {
+	if len(x) == 0 {
+		return
+	}
+
+	var (
+		a1 = math.Exp(-1.414 * math.Pi / DType(period))
+		c2 = 2 * a1 * math.Cos(1.414*math.Pi/DType(period))
+		c3 = -a1 * a1
+		c1 = 1 - c2 - c3
+
+		prev = x[0]
+	)
+
+	for i := range x {
+		cur := x[i]
+
+		switch i {
+		case 0, 1:
+			dst[i] = cur
+		default:
+			dst[i] = c1*(cur+prev)/2 + c2*dst[i-1] + c3*dst[i-2]
+		}
+
+		prev = cur
+	}
+}
@@ -0,0 +1,241 @@
+package fta
+
+import (
+	"sort"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// ApEn is the rolling approximate entropy of the close over the period.
+// M is the length of compared templates, usually 2. Templates match when their values differ by not more than
+// r standard deviations of the window, usually 0.2.
+// Low values mean regular and predictable series, high values mean random series.
+//
+// Distances between templates are computed once per pair and kept sorted per template while the window slides,
+// so matches of each template are counted by binary search.
+func ApEn(close series.Data, period, m int, r float64) (apen series.Data) {
+	var (
+		values = close.Values()
+		short  = newTemplateRows(values, m, period-m+1)
+		long   = newTemplateRows(values, m+1, period-m)
+	)
+
+	apen = rollingEntropy(close, period, m, r, func(start int, tolerance DType) DType {
+		short.slide(start)
+		long.slide(start)
+
+		return short.phi(tolerance) - long.phi(tolerance)
+	})
+
+	return apen
+}
+
+// SampEn is the rolling sample entropy of the close over the period.
+// Parameters are the same as of ApEn. Unlike ApEn self-matches aren't counted, so it's less biased on short windows.
+// The value is NaN when there are no matching templates.
+func SampEn(close series.Data, period, m int, r float64) (sampen series.Data) {
+	// Both counts use the same N-m templates, so A and B are comparable.
+	var (
+		values = close.Values()
+		short  = newTemplateRows(values, m, period-m)
+		long   = newTemplateRows(values, m+1, period-m)
+	)
+
+	sampen = rollingEntropy(close, period, m, r, func(start int, tolerance DType) DType {
+		short.slide(start)
+		long.slide(start)
+
+		// Every pair is counted by both of its templates.
+		b, a := short.matches(tolerance), long.matches(tolerance)
+		if a == 0 || b == 0 {
+			return math.NaN()
+		}
+
+		return math.Log(DType(b) / DType(a))
+	})
+
+	return sampen
+}
+
+// rollingEntropy calls fn with the start and the tolerance of every window of the period.
+// Windows with NaN values, too short windows and the first period-1 values are NaN.
+func rollingEntropy(close series.Data, period, m int, r float64, fn func(start int, tolerance DType) DType) series.Data {
+	result := close.Clone()
+
+	var (
+		values = close.Values()
+		out    = result.Values()
+	)
+
+	for i := range out {
+		start := i - period + 1
+		if start < 0 || period <= m+1 {
+			out[i] = math.NaN()
+			continue
+		}
+
+		tolerance, ok := entropyTolerance(values[start:i+1], r)
+		if !ok {
+			out[i] = math.NaN()
+			continue
+		}
+
+		out[i] = fn(start, tolerance)
+	}
+
+	return result
+}
+
+// templateRows keeps distances between templates of the given length starting in the sliding window.
+// Every template has the row of sorted distances to other templates of the window.
+type templateRows struct {
+	values []DType
+	// length is the length of templates, size is the number of templates of the window.
+	length, size int
+	// first is the start of the first template, rows are indexed by starts modulo size.
+	first int
+	rows  [][]DType
+	valid bool
+}
+
+func newTemplateRows(values []DType, length, size int) *templateRows {
+	return &templateRows{values: values, length: length, size: size}
+}
+
+// slide moves templates to the window starting at start.
+// The next window reuses distances of the previous one, otherwise rows are rebuilt.
+func (t *templateRows) slide(start int) {
+	if t.valid && start == t.first {
+		return
+	}
+
+	if !t.valid || start != t.first+1 {
+		t.rebuild(start)
+		return
+	}
+
+	var (
+		out = t.first
+		in  = t.first + t.size
+	)
+
+	// The row of the leaving template is reused by the entering one, they have the same index modulo size.
+	row := t.rows[out%t.size][:0]
+
+	for s := out + 1; s < in; s++ {
+		k := s % t.size
+		t.rows[k] = removeSorted(t.rows[k], t.distance(s, out))
+
+		d := t.distance(s, in)
+		t.rows[k] = insertSorted(t.rows[k], d)
+		row = append(row, d)
+	}
+
+	sort.Sort(series.DTypeSlice(row))
+	t.rows[in%t.size] = row
+	t.first = start
+}
+
+func (t *templateRows) rebuild(start int) {
+	if t.rows == nil {
+		t.rows = make([][]DType, t.size)
+	}
+	for k := range t.rows {
+		t.rows[k] = t.rows[k][:0]
+	}
+
+	for s := start; s < start+t.size; s++ {
+		for u := s + 1; u < start+t.size; u++ {
+			d := t.distance(s, u)
+			t.rows[s%t.size] = append(t.rows[s%t.size], d)
+			t.rows[u%t.size] = append(t.rows[u%t.size], d)
+		}
+	}
+
+	for _, row := range t.rows {
+		sort.Sort(series.DTypeSlice(row))
+	}
+
+	t.first = start
+	t.valid = true
+}
+
+// distance is the Chebyshev distance between templates starting at s and u.
+func (t *templateRows) distance(s, u int) (d DType) {
+	for k := 0; k < t.length; k++ {
+		if diff := math.Abs(t.values[s+k] - t.values[u+k]); diff > d {
+			d = diff
+		}
+	}
+	return d
+}
+
+// count returns the number of templates matching the k-th one within tolerance, the self-match excluded.
+func (t *templateRows) count(k int, tolerance DType) int {
+	row := t.rows[k]
+	return sort.Search(len(row), func(i int) bool { return row[i] > tolerance })
+}
+
+// phi is the mean logarithm of the fractions of templates matching each template, self-matches included.
+func (t *templateRows) phi(tolerance DType) DType {
+	var sum DType
+
+	for s := t.first; s < t.first+t.size; s++ {
+		sum += math.Log(DType(t.count(s%t.size, tolerance)+1) / DType(t.size))
+	}
+
+	return sum / DType(t.size)
+}
+
+// matches returns the number of matching pairs of different templates.
+func (t *templateRows) matches(tolerance DType) int {
+	var n int
+
+	for k := range t.rows {
+		n += t.count(k, tolerance)
+	}
+
+	return n / 2
+}
+
+// insertSorted inserts v into sorted values.
+func insertSorted(values []DType, v DType) []DType {
+	i := sort.Search(len(values), func(i int) bool { return values[i] >= v })
+	values = append(values, 0)
+	copy(values[i+1:], values[i:])
+	values[i] = v
+	return values
+}
+
+// removeSorted removes single v from sorted values.
+func removeSorted(values []DType, v DType) []DType {
+	i := sort.Search(len(values), func(i int) bool { return values[i] >= v })
+	if i == len(values) || values[i] != v {
+		return values
+	}
+	return append(values[:i], values[i+1:]...)
+}
+
+// entropyTolerance returns r sample standard deviations of values, windows with NaN values are rejected.
+func entropyTolerance(values []DType, r float64) (tolerance DType, ok bool) {
+	if len(values) < 2 {
+		return 0, false
+	}
+
+	var mean DType
+	for _, v := range values {
+		if series.IsNA(v) {
+			return 0, false
+		}
+		mean += v
+	}
+	mean /= DType(len(values))
+
+	var ss DType
+	for _, v := range values {
+		ss += (v - mean) * (v - mean)
+	}
+
+	return DType(r) * math.Sqrt(ss/DType(len(values)-1)), true
+}
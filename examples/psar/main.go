@@ -1,8 +1,6 @@
 package main
 
 import (
-	"compress/gzip"
-	"encoding/csv"
 	"image/color"
 	"os"
 	"time"
@@ -16,7 +14,6 @@ import (
 	"gonum.org/v1/plot/vg/vgimg"
 
 	"github.com/WinPooh32/fta"
-	"github.com/WinPooh32/series"
 )
 
 func main() {
@@ -36,8 +33,8 @@ func main() {
 	ohlcv = ohlcv.Slice(period, ohlcv.Len())
 	sma = sma.Slice(period, sma.Len())
 
-	psarBull = shrink(psarBull.Slice(period, psarBull.Len()))
-	psarBear = shrink(psarBear.Slice(period, psarBear.Len()))
+	psarBull = fta.Dropna(psarBull.Slice(period, psarBull.Len()))
+	psarBear = fta.Dropna(psarBear.Slice(period, psarBear.Len()))
 
 	// Prepare data for rendering.
 	upperLine, lowerLine := makePsarScatters(psarBull, psarBear)
@@ -74,24 +71,11 @@ func checkErr(err error) {
 	}
 }
 
-func newReader(file string) *csv.Reader {
-	f, err := os.Open(file)
-	checkErr(err)
-
-	gzReader, err := gzip.NewReader(f)
-	checkErr(err)
-
-	csvReader := csv.NewReader(gzReader)
-	csvReader.Comma = ','
-	csvReader.ReuseRecord = true
-
-	return csvReader
-}
-
 func readOHLCV(file string) fta.OHLCV {
-	csvReader := newReader(file)
-
-	ohlcv, err := fta.ReadCSV(csvReader, int64(time.Minute), fta.Seconds)
+	ohlcv, err := fta.ReadCSVFile(file, fta.CSVOptions{
+		Freq:     int64(time.Minute),
+		UnixTime: fta.Seconds,
+	})
 	checkErr(err)
 
 	ohlcv = ohlcv.Resample(int64(time.Hour))
@@ -162,20 +146,3 @@ func render(file string, pricePlot, volumePlot *plot.Plot) {
 	_, err = png.WriteTo(w)
 	checkErr(err)
 }
-
-func shrink(data series.Data) series.Data {
-	values := data.Values()
-	index := data.Index()
-
-	valuesShrinked := make([]fta.DType, 0, len(values))
-	indexShrinked := make([]int64, 0, len(index))
-
-	for i, v := range values {
-		if v > 0 {
-			valuesShrinked = append(valuesShrinked, v)
-			indexShrinked = append(indexShrinked, index[i])
-		}
-	}
-
-	return series.MakeData(data.Freq(), indexShrinked, valuesShrinked)
-}
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"compress/gzip"
-	"encoding/csv"
 	"image/color"
 	"os"
 	"time"
@@ -80,24 +78,11 @@ func checkErr(err error) {
 	}
 }
 
-func newReader(file string) *csv.Reader {
-	f, err := os.Open(file)
-	checkErr(err)
-
-	gzReader, err := gzip.NewReader(f)
-	checkErr(err)
-
-	csvReader := csv.NewReader(gzReader)
-	csvReader.Comma = ','
-	csvReader.ReuseRecord = true
-
-	return csvReader
-}
-
 func readOHLCV(file string) fta.OHLCV {
-	csvReader := newReader(file)
-
-	ohlcv, err := fta.ReadCSV(csvReader, int64(time.Minute), fta.Seconds)
+	ohlcv, err := fta.ReadCSVFile(file, fta.CSVOptions{
+		Freq:     int64(time.Minute),
+		UnixTime: fta.Seconds,
+	})
 	checkErr(err)
 
 	ohlcv = ohlcv.Resample(int64(time.Hour))
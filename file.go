@@ -0,0 +1,213 @@
+//go:build !js
+// +build !js
+
+package fta
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Decompressor wraps reader of compressed stream.
+type Decompressor func(r io.Reader) (io.ReadCloser, error)
+
+type decompressorEntry struct {
+	name         string
+	magic        []byte
+	decompressor Decompressor
+}
+
+var (
+	decompressorsMu sync.RWMutex
+	decompressors   = []decompressorEntry{
+		{name: "gzip", magic: []byte{0x1f, 0x8b}, decompressor: gzipDecompressor},
+	}
+)
+
+var (
+	zipMagic  = []byte("PK\x03\x04")
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// RegisterDecompressor registers decompressor of the format identified by magic bytes at the beginning of file.
+// By default the package doesn't depend on third-party compression libraries, so zstd is decompressed
+// only when built with zstd tag or registered explicitly, e.g.:
+//
+//	fta.RegisterDecompressor("zstd", []byte{0x28, 0xb5, 0x2f, 0xfd}, func(r io.Reader) (io.ReadCloser, error) {
+//		d, err := zstd.NewReader(r)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return d.IOReadCloser(), nil
+//	})
+func RegisterDecompressor(name string, magic []byte, decompressor Decompressor) {
+	decompressorsMu.Lock()
+	defer decompressorsMu.Unlock()
+
+	decompressors = append(decompressors, decompressorEntry{
+		name:         name,
+		magic:        append([]byte(nil), magic...),
+		decompressor: decompressor,
+	})
+}
+
+// ReadCSVFile reads ohlcv from csv file.
+// Compression is detected by magic bytes: gzip and zip (the first file of archive) are supported out of the box,
+// zstd is supported when built with zstd tag, other formats can be added by RegisterDecompressor.
+// Progress of options receives the number of bytes read from the file, for zip archives it's the number of decompressed bytes.
+func ReadCSVFile(path string, opts CSVOptions) (ohlcv OHLCV, err error) {
+	var tracker *ProgressTracker
+	if opts.Progress != nil {
+		tracker = NewProgressTracker(opts.Progress, 0)
+	}
+
+	rc, err := openFile(path, tracker)
+	if err != nil {
+		return ohlcv, err
+	}
+	defer rc.Close()
+
+	reader := csv.NewReader(rc)
+	reader.ReuseRecord = true
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+
+	ohlcv, err = ReadCSVWithOptions(reader, opts)
+	if err != nil {
+		return ohlcv, fmt.Errorf("%s: %w", path, err)
+	}
+
+	tracker.Finish()
+
+	return ohlcv, nil
+}
+
+// OpenFile opens file for reading and decompresses its content transparently.
+// See ReadCSVFile for the supported compression formats.
+func OpenFile(path string) (io.ReadCloser, error) {
+	return openFile(path, nil)
+}
+
+// openFile opens file like OpenFile does and reports read bytes to the tracker.
+// Total of the tracker is set to the size of the file.
+func openFile(path string, tracker *ProgressTracker) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+
+	var r io.Reader = f
+
+	if tracker != nil {
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("stat file: %w", err)
+		}
+		tracker.total = info.Size()
+		r = progressReader{r: f, tracker: tracker}
+	}
+
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(4)
+	if err != nil && !errors.Is(err, io.EOF) {
+		f.Close()
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	if bytes.HasPrefix(magic, zipMagic) {
+		f.Close()
+		return openZip(path, tracker)
+	}
+
+	decompressorsMu.RLock()
+	defer decompressorsMu.RUnlock()
+
+	for i := len(decompressors) - 1; i >= 0; i-- {
+		entry := decompressors[i]
+
+		if len(entry.magic) == 0 || !bytes.HasPrefix(magic, entry.magic) {
+			continue
+		}
+
+		rc, err := entry.decompressor(br)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s: %w", entry.name, err)
+		}
+
+		return multiCloser{Reader: rc, closers: []io.Closer{rc, f}}, nil
+	}
+
+	if bytes.HasPrefix(magic, zstdMagic) || strings.EqualFold(filepath.Ext(path), ".zst") {
+		f.Close()
+		return nil, errors.New("zstd: decompressor is not registered, build with zstd tag or see RegisterDecompressor")
+	}
+
+	return multiCloser{Reader: br, closers: []io.Closer{f}}, nil
+}
+
+func openZip(path string, tracker *ProgressTracker) (io.ReadCloser, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("zip: %w", err)
+	}
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			archive.Close()
+			return nil, fmt.Errorf("zip: %w", err)
+		}
+
+		var r io.Reader = rc
+
+		if tracker != nil {
+			tracker.total = int64(file.UncompressedSize64)
+			tracker.done = 0
+			r = progressReader{r: rc, tracker: tracker}
+		}
+
+		return multiCloser{Reader: r, closers: []io.Closer{rc, archive}}, nil
+	}
+
+	archive.Close()
+
+	return nil, errors.New("zip: archive has no files")
+}
+
+func gzipDecompressor(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// multiCloser closes all closers in order.
+type multiCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (mc multiCloser) Close() error {
+	var err error
+	for _, c := range mc.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
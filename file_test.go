@@ -0,0 +1,75 @@
+//go:build !js
+// +build !js
+
+package fta
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadCSVFile(t *testing.T) {
+	const text = "time,open,high,low,close,volume\n60,1,3,0.5,2,10\n120,2,4,1.5,3,11\n"
+
+	want := []Bar{
+		{Time: int64(time.Minute), Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 10},
+		{Time: int64(2 * time.Minute), Open: 2, High: 4, Low: 1.5, Close: 3, Volume: 11},
+	}
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(text))
+	zw.Close()
+
+	var zipped bytes.Buffer
+	aw := zip.NewWriter(&zipped)
+	w, err := aw.Create("bars.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(text))
+	aw.Close()
+
+	// Compression is detected by content, names are misleading on purpose.
+	files := map[string][]byte{
+		"plain.csv":  []byte(text),
+		"gzip.csv":   gz.Bytes(),
+		"zip.csv.gz": zipped.Bytes(),
+	}
+
+	dir := t.TempDir()
+
+	for name, data := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			var last Progress
+			ohlcv, err := ReadCSVFile(path, CSVOptions{
+				Header:   true,
+				Progress: func(p Progress) { last = p },
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertBars(t, ohlcv, want)
+
+			// The final report covers the whole input.
+			if last.Total == 0 || last.Done != last.Total {
+				t.Fatalf("got final progress %+v", last)
+			}
+		})
+	}
+
+	if _, err := ReadCSVFile(filepath.Join(dir, "missing.csv"), CSVOptions{}); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got error %v, want not exist", err)
+	}
+}
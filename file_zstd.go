@@ -0,0 +1,22 @@
+//go:build zstd && !js
+// +build zstd,!js
+
+package fta
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func init() {
+	RegisterDecompressor("zstd", zstdMagic, zstdDecompressor)
+}
+
+func zstdDecompressor(r io.Reader) (io.ReadCloser, error) {
+	d, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return d.IOReadCloser(), nil
+}
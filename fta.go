@@ -1,6 +1,8 @@
 package fta
 
 import (
+	"sort"
+
 	"github.com/WinPooh32/series"
 	"github.com/WinPooh32/series/math"
 )
@@ -10,6 +12,10 @@ type DType = series.DType
 // Simple moving average - rolling mean in pandas lingo. Also known as 'MA'.
 // The simple moving average (SMA) is the most basic of the moving averages used for trading.
 func SMA(column series.Data, period int) (sma series.Data) {
+	return DefaultBackend.SMA(column, period)
+}
+
+func goSMA(column series.Data, period int) (sma series.Data) {
 	sma = column.Rolling(period).Mean()
 	return sma
 }
@@ -17,7 +23,7 @@ func SMA(column series.Data, period int) (sma series.Data) {
 // Simple moving median, an alternative to moving average. SMA, when used to estimate the underlying trend in a time series,
 // is susceptible to rare events such as rapid shocks or other anomalies. A more robust estimate of the trend is the simple moving median over n time periods.
 func SMM(column series.Data, period int) (smm series.Data) {
-	smm = column.Rolling(period).Median()
+	smm = rollingMedian(column, period)
 	return smm
 }
 
@@ -29,6 +35,46 @@ func SSMA(column series.Data, period int, adjust bool) (ssma series.Data) {
 	return ssma
 }
 
+// RMA is Wilder's smoothed moving average: rma = (rma*(period-1) + value) / period seeded by the simple mean
+// of the first period values exactly as in TA-Lib, unlike SSMA seeded by the first value.
+// Leading NaN values are skipped and the next period-1 values are NaN. Later NaN values are NaN in the result
+// and don't change the average.
+func RMA(column series.Data, period int) (rma series.Data) {
+	rma = column.Clone()
+
+	var (
+		values = rma.Values()
+		n      = DType(period)
+		avg    DType
+		count  int
+	)
+
+	for i, v := range values {
+		if series.IsNA(v) {
+			if count > 0 && count < period {
+				count, avg = 0, 0
+			}
+			values[i] = math.NaN()
+			continue
+		}
+
+		switch count++; {
+		case count < period:
+			avg += v
+			values[i] = math.NaN()
+			continue
+		case count == period:
+			avg = (avg + v) / n
+		default:
+			avg = (avg*(n-1) + v) / n
+		}
+
+		values[i] = avg
+	}
+
+	return rma
+}
+
 // Exponential Weighted Moving Average - Like all moving average indicators, they are much better suited for trending markets.
 // When the market is in a strong and sustained uptrend, the EMA indicator line will also show an uptrend and vice-versa for a down trend.
 // EMAs are commonly used in conjunction with other indicators to confirm significant market moves and to gauge their validity.
@@ -42,6 +88,10 @@ func EMA(column series.Data, period int, adjust bool) (ema series.Data) {
 // WMA stands for weighted moving average. It helps to smooth the price curve for better trend identification.
 // It places even greater importance on recent data than the EMA does.
 func WMA(column series.Data, period int) (wma series.Data) {
+	return DefaultBackend.WMA(column, period)
+}
+
+func goWMA(column series.Data, period int) (wma series.Data) {
 	denominator := DType(period*(period+1)) / 2.0
 
 	weights := series.MakeValues(make([]DType, period))
@@ -89,9 +139,9 @@ func HMA(column series.Data, period int) (hma series.Data) {
 // is a pure momentum oscillator that measures the percent change in price from one period to the next.
 // The ROC calculation compares the current price with the price “n” periods ago.
 func ROC(column series.Data, period int) (roc series.Data) {
-	diff := column.Diff(period)
-	shift := column.Shift(period)
-	roc = diff.Div(shift).MulScalar(100)
+	diff := column.Clone().Diff(period)
+	shift := column.Clone().Shift(period)
+	roc = div(diff, shift).MulScalar(100)
 	return roc
 }
 
@@ -122,14 +172,14 @@ func KST(column series.Data, r1, r2, r3, r4 int) (k, signal series.Data) {
 func FISH(low, high series.Data, period int, adjust bool) (fish series.Data) {
 	var (
 		med      = high.Clone().Add(low).MulScalar(0.5)
-		ndaylow  = med.Rolling(period).Min()
-		ndayhigh = med.Rolling(period).Max()
-		raw      = med.Sub(ndaylow).Div(ndayhigh.Sub(ndaylow)).MulScalar(2).SubScalar(1)
+		ndaylow  = RollingMin(med, period)
+		ndayhigh = RollingMax(med, period)
+		raw      = div(med.Sub(ndaylow), ndayhigh.Sub(ndaylow)).MulScalar(2).SubScalar(1)
 		smooth   = raw.EWM(series.AlphaSpan, 5, adjust, false).Mean().Fillna(0)
 
 		a   = smooth.Clone().AddScalar(1)       // 1 + smooth
 		b   = smooth.MulScalar(-1).AddScalar(1) // 1 - smooth
-		log = a.Div(b).Log()
+		log = div(a, b).Log()
 	)
 	fish = log.EWM(series.AlphaSpan, 3, adjust, false).Mean()
 	return fish
@@ -168,11 +218,26 @@ func BBANDS(column series.Data, ma series.Data, period int, stdMultiplier float6
 	return upper, lower
 }
 
+// BBANDSFull returns Bollinger Bands together with the middle band, a copy of ma, and the band width.
+// The width is (upper-lower)/middle, it narrows to multi-month lows during squeezes that often precede breakouts.
+func BBANDSFull(column series.Data, ma series.Data, period int, stdMultiplier float64) (upper, middle, lower, width series.Data) {
+	upper, lower = BBANDS(column, ma, period, stdMultiplier)
+	middle = ma.Clone()
+	width = div(upper.Clone().Sub(lower), middle)
+	return upper, middle, lower, width
+}
+
+// BBWIDTH is the Bollinger band width: the distance between the bands relative to the middle band.
+func BBWIDTH(column series.Data, ma series.Data, period int, stdMultiplier float64) (width series.Data) {
+	_, _, _, width = BBANDSFull(column, ma, period, stdMultiplier)
+	return width
+}
+
 // %b (pronounced 'percent b') is derived from the formula for Stochastics and shows where price is in relation to the bands.
 // %b equals 1 at the upper band and 0 at the lower band.
 func PercentB(column series.Data, ma series.Data, period int, stdMultiplier float64) (percentB series.Data) {
-	bbLower, bbUpper := BBANDS(column, ma, period, stdMultiplier)
-	percentB = column.Clone().Sub(bbLower).Div(bbUpper.Sub(bbLower))
+	bbUpper, bbLower := BBANDS(column, ma, period, stdMultiplier)
+	percentB = div(column.Clone().Sub(bbLower), bbUpper.Sub(bbLower))
 	return percentB
 }
 
@@ -180,6 +245,9 @@ func PercentB(column series.Data, ma series.Data, period int, stdMultiplier floa
 // RSI oscillates between zero and 100. Traditionally, and according to Wilder, RSI is considered overbought when above 70 and oversold when below 30.
 // Signals can also be generated by looking for divergences, failure swings and centerline crossovers.
 // RSI can also be used to identify the general trend.
+//
+// The first value and flat windows without gains and losses are 0/0 and follow DivByZero policy: they are NaN
+// by default, while earlier versions returned 0 for them. Set DivByZero to DivZero to keep zeros.
 func RSI(column series.Data, period int, adjust bool) (rsi series.Data) {
 	column = column.Clone()
 
@@ -206,13 +274,46 @@ func RSI(column series.Data, period int, adjust bool) (rsi series.Data) {
 		alphaParam = 1.0 / float64(period)
 		gain       = up.EWM(series.Alpha, DType(alphaParam), adjust, true).Mean()
 		loss       = down.Abs().EWM(series.Alpha, DType(alphaParam), adjust, true).Mean()
+	)
+
+	rsi = relativeStrength(gain, loss)
+
+	return rsi
+}
+
+// relativeStrength returns 100*gain/(gain+loss) in place of gain, it's 100 when there are no losses.
+// Flat windows without gains and losses follow DivByZero policy.
+func relativeStrength(gain, loss series.Data) series.Data {
+	total := gain.Clone().Add(loss)
+	return div(gain, total).MulScalar(100)
+}
+
+// RSIWilder is RSI calculated exactly as in Wilder's book and TA-Lib.
+// Average gain and loss are seeded by simple means of the first period changes and then smoothed by Wilder's method.
+// The first period values are NaN, flat windows without gains and losses follow DivByZero policy.
+func RSIWilder(column series.Data, period int) (rsi series.Data) {
+	return DefaultBackend.RSIWilder(column, period)
+}
 
-		rs = gain.Div(loss).Fillna(0)
+func goRSIWilder(column series.Data, period int) (rsi series.Data) {
+	var (
+		up   = column.Clone().Diff(1)
+		down = up.Clone()
 	)
 
-	rsi = rs.Apply(func(v DType) DType {
-		return 100 - (100 / (1 + v))
-	})
+	upValues := up.Values()
+	downValues := down.Values()
+
+	for i, change := range upValues {
+		if change < 0 {
+			upValues[i] = 0
+			downValues[i] = -change
+		} else if change >= 0 {
+			downValues[i] = 0
+		}
+	}
+
+	rsi = relativeStrength(RMA(up, period), RMA(down, period))
 
 	return rsi
 }
@@ -220,12 +321,11 @@ func RSI(column series.Data, period int, adjust bool) (rsi series.Data) {
 // Connors RSI (CRSI) is a technical analysis indicator created by Larry Connors that is actually a composite of three separate components.
 // The Relative Strength Index (RSI), developed by J. Welles Wilder, plays an integral role in Connors RSI.
 // Connors RSI outputs a value between 0 and 100, which is then used to identify short-term overbought and oversold conditions.
+// The first value and flat stretches of prices are NaN under the default DivByZero policy, see RSI.
 func CRSI(close series.Data, period int, periodUpDown int, periodrRoc int, adjust bool) (crsi series.Data) {
 	var streak DType
 
-	close = close.Clone()
-
-	updown := close.Diff(1).Apply(func(v DType) DType {
+	updown := close.Clone().Diff(1).Apply(func(v DType) DType {
 		switch {
 		case v > 0:
 			if streak <= 0 {
@@ -273,35 +373,76 @@ func VZO(price, volume series.Data, period int, adjust bool) (vzo series.Data) {
 	dvma := v.EWM(series.AlphaSpan, DType(period), adjust, false).Mean()
 	vma := volume.Clone().EWM(series.AlphaSpan, DType(period), adjust, false).Mean()
 
-	vzo = dvma.MulScalar(100).Div(vma)
+	vzo = div(dvma.MulScalar(100), vma)
 
 	return vzo
 }
 
+// StochOption configures STOCH and STOCHD.
+type StochOption func(opts *stochOptions)
+
+type stochOptions struct {
+	scale     DType
+	zeroRange DType
+	fillZero  bool
+}
+
+// StochPercent scales the oscillator to 0-100 range like charting platforms do, the default range is 0-1.
+func StochPercent() StochOption {
+	return func(opts *stochOptions) {
+		opts.scale = 100
+	}
+}
+
+// StochZeroRange sets the value of the oscillator when the highest high equals the lowest low.
+// The value is in the output scale, e.g. 50 with StochPercent. By default such values are NaN.
+func StochZeroRange(value DType) StochOption {
+	return func(opts *stochOptions) {
+		opts.zeroRange = value
+		opts.fillZero = true
+	}
+}
+
 // Stochastic oscillator %K
 // The stochastic oscillator is a momentum indicator comparing the closing price of a security
 // to the range of its prices over a certain period of time.
 // The sensitivity of the oscillator to market movements is reducible by adjusting that time
 // period or by taking a moving average of the result.
-func STOCH(high, low, close series.Data, period int) (stoch series.Data) {
+func STOCH(high, low, close series.Data, period int, options ...StochOption) (stoch series.Data) {
+	opts := stochOptions{scale: 1}
+	for _, option := range options {
+		option(&opts)
+	}
+
 	close = close.Clone()
 
-	highestHigh := high.Rolling(period).Max()
-	lowestLow := low.Rolling(period).Min()
+	highestHigh := RollingMax(high, period)
+	lowestLow := RollingMin(low, period)
 
-	stoch = close.
-		Sub(lowestLow).
-		Div(
-			highestHigh.Sub(lowestLow),
-		)
+	var (
+		rng    = highestHigh.Sub(lowestLow)
+		ranges = rng.Values()
+	)
+
+	stoch = div(close.Sub(lowestLow), rng).
+		MulScalar(opts.scale)
+
+	if opts.fillZero {
+		values := stoch.Values()
+		for i, r := range ranges {
+			if r == 0 {
+				values[i] = opts.zeroRange
+			}
+		}
+	}
 
 	return stoch
 }
 
 // Stochastic oscillator %D
 // STOCH%D is a 3 period simple moving average of %K.
-func STOCHD(high, low, close series.Data, period int) (stochd series.Data) {
-	return STOCH(high, low, close, period).Rolling(period).Mean()
+func STOCHD(high, low, close series.Data, period int, options ...StochOption) (stochd series.Data) {
+	return STOCH(high, low, close, period, options...).Rolling(period).Mean()
 }
 
 // StochRSI is an oscillator that measures the level of RSI relative to its high-low range over a set time period.
@@ -330,7 +471,7 @@ func ADL(high, low, close series.Data) (adl series.Data) {
 	subHighClose := high.Clone().Sub(close)
 	subHighLow := high.Clone().Sub(low)
 
-	mfv := subCloseLow.Sub(subHighClose).Div(subHighLow)
+	mfv := div(subCloseLow.Sub(subHighClose), subHighLow)
 
 	return mfv.Cumsum()
 }
@@ -355,6 +496,7 @@ func CHAIKIN(high, low, close series.Data, adjust bool) (chaikin series.Data) {
 // Traders also refer to the indicator as the parabolic stop and reverse, parabolic SAR, or PSAR.
 // https://www.investopedia.com/terms/p/parabolicindicator.asp
 // https://virtualizedfrog.wordpress.com/2014/12/09/parabolic-sar-implementation-in-python/
+// Values of bullSeries and bearSeries are NaN when the side is inactive, use Dropna to remove them.
 func PSAR(high, low, close series.Data, iaf float64, maxaf float64) (psarSeries, bullSeries, bearSeries series.Data) {
 	length := close.Len()
 
@@ -365,6 +507,11 @@ func PSAR(high, low, close series.Data, iaf float64, maxaf float64) (psarSeries,
 	psarBull := make([]DType, length)
 	psarBear := make([]DType, length)
 
+	for i := range psarBull {
+		psarBull[i] = math.NaN()
+		psarBear[i] = math.NaN()
+	}
+
 	bull := true
 	af := DType(iaf)
 	hp := highValues[0]
@@ -438,3 +585,1199 @@ func PSAR(high, low, close series.Data, iaf float64, maxaf float64) (psarSeries,
 
 	return psarSeries, bullSeries, bearSeries
 }
+
+// BREAKOUT is the N-bar breakout system of the Turtle traders based on Donchian channel penetrations.
+// Long entry is the close above the highest high of previous entryPeriod bars, long exit is the close below
+// the lowest low of previous exitPeriod bars. Short signals are mirrored.
+// Signals are 1 when triggered and 0 otherwise.
+func BREAKOUT(high, low, close series.Data, entryPeriod, exitPeriod int) (longEntry, longExit, shortEntry, shortExit series.Data) {
+	var (
+		entryUpper = RollingMax(high, entryPeriod).Shift(1).Values()
+		entryLower = RollingMin(low, entryPeriod).Shift(1).Values()
+		exitUpper  = RollingMax(high, exitPeriod).Shift(1).Values()
+		exitLower  = RollingMin(low, exitPeriod).Shift(1).Values()
+	)
+
+	longEntry = close.Clone()
+	longExit = close.Clone()
+	shortEntry = close.Clone()
+	shortExit = close.Clone()
+
+	var (
+		le = longEntry.Values()
+		lx = longExit.Values()
+		se = shortEntry.Values()
+		sx = shortExit.Values()
+	)
+
+	signal := func(ok bool) DType {
+		if ok {
+			return 1
+		}
+		return 0
+	}
+
+	for i, c := range close.Values() {
+		le[i] = signal(c > entryUpper[i])
+		lx[i] = signal(c < exitLower[i])
+		se[i] = signal(c < entryLower[i])
+		sx[i] = signal(c > exitUpper[i])
+	}
+
+	return longEntry, longExit, shortEntry, shortExit
+}
+
+// SAFEZONE is Elder's SafeZone stops which are placed beyond the level of market noise.
+// The noise is the average penetration of the previous bar's low (high) over the period, counted only on bars with penetration.
+// The long stop is the previous low minus the multiplied average downside penetration, the short stop is mirrored.
+// As Elder recommends, the long stop isn't lowered and the short stop isn't raised over the last 3 bars.
+func SAFEZONE(high, low series.Data, period int, multiplier float64) (longStop, shortStop series.Data) {
+	const protect = 3
+
+	var (
+		highValues = high.Values()
+		lowValues  = low.Values()
+
+		rawLong  = low.Clone()
+		rawShort = high.Clone()
+
+		longValues  = rawLong.Values()
+		shortValues = rawShort.Values()
+
+		k = DType(multiplier)
+	)
+
+	for i := range lowValues {
+		if i < period+1 {
+			longValues[i] = math.NaN()
+			shortValues[i] = math.NaN()
+			continue
+		}
+
+		var (
+			downSum, upSum     DType
+			downCount, upCount int
+		)
+
+		for j := i - period; j < i; j++ {
+			if d := lowValues[j-1] - lowValues[j]; d > 0 {
+				downSum += d
+				downCount++
+			}
+			if u := highValues[j] - highValues[j-1]; u > 0 {
+				upSum += u
+				upCount++
+			}
+		}
+
+		var downAvg, upAvg DType
+		if downCount > 0 {
+			downAvg = downSum / DType(downCount)
+		}
+		if upCount > 0 {
+			upAvg = upSum / DType(upCount)
+		}
+
+		longValues[i] = lowValues[i-1] - k*downAvg
+		shortValues[i] = highValues[i-1] + k*upAvg
+	}
+
+	longStop = RollingMax(rawLong, protect)
+	shortStop = RollingMin(rawShort, protect)
+
+	return longStop, shortStop
+}
+
+// DEVSTOP is Cynthia Kase's Dev-Stop which places stops at 1, 2 and 3 standard deviations of the two-bar true range.
+// The two-bar true range is the distance between the highest high and the lowest low of the current and the previous bars.
+// Long stops are below the highest close of the period, short stops are above the lowest close.
+func DEVSTOP(high, low, close series.Data, period int) (long1, long2, long3, short1, short2, short3 series.Data) {
+	var (
+		highValues = high.Values()
+		lowValues  = low.Values()
+
+		dtr       = high.Clone()
+		dtrValues = dtr.Values()
+	)
+
+	for i := range dtrValues {
+		if i == 0 {
+			dtrValues[i] = math.NaN()
+			continue
+		}
+		dtrValues[i] = math.Max(highValues[i], highValues[i-1]) - math.Min(lowValues[i], lowValues[i-1])
+	}
+
+	var (
+		avg = dtr.Rolling(period).Mean()
+		std = dtr.Rolling(period).Std(avg, 1)
+
+		highestClose = RollingMax(close, period)
+		lowestClose  = RollingMin(close, period)
+	)
+
+	stop := func(base series.Data, sign, deviations DType) series.Data {
+		offset := std.Clone().MulScalar(deviations).Add(avg).MulScalar(sign)
+		return base.Clone().Add(offset)
+	}
+
+	long1 = stop(highestClose, -1, 1)
+	long2 = stop(highestClose, -1, 2)
+	long3 = stop(highestClose, -1, 3)
+
+	short1 = stop(lowestClose, 1, 1)
+	short2 = stop(lowestClose, 1, 2)
+	short3 = stop(lowestClose, 1, 3)
+
+	return long1, long2, long3, short1, short2, short3
+}
+
+// RS is the relative strength of the asset versus the benchmark, i.e. the ratio of their prices.
+// The benchmark is aligned to timestamps of the asset, missing benchmark prices are filled forward.
+func RS(asset, benchmark series.Data) (rs series.Data) {
+	rs = div(asset.Clone(), Reindex(benchmark, asset, FillForward))
+	return rs
+}
+
+// MansfieldRS is the relative strength normalized by its simple moving average and expressed in percents.
+// Values above zero mean that the asset outperforms the benchmark. Stan Weinstein uses the period of 52 weeks.
+func MansfieldRS(asset, benchmark series.Data, period int) (mrs series.Data) {
+	rs := RS(asset, benchmark)
+
+	mrs = div(rs.Clone(), rs.Rolling(period).Mean()).
+		SubScalar(1).
+		MulScalar(100)
+
+	return mrs
+}
+
+// VWMACD is the volume-weighted MACD. Fast and slow lines are volume-weighted EMAs of the price,
+// i.e. EMAs of price multiplied by volume divided by EMAs of volume.
+// The signal line is EMA of the VWMACD line.
+func VWMACD(close, volume series.Data, periodFast, periodSlow, signal float64, adjust bool) (vwmacd, vwmacdSignal series.Data) {
+	var (
+		fast = vwema(close, volume, periodFast, adjust)
+		slow = vwema(close, volume, periodSlow, adjust)
+	)
+
+	vwmacd = fast.Sub(slow)
+	vwmacdSignal = vwmacd.EWM(series.AlphaSpan, DType(signal), adjust, false).Mean()
+
+	return vwmacd, vwmacdSignal
+}
+
+// VWRSI is the volume-weighted RSI. Gains and losses are multiplied by volume of their bars before smoothing,
+// so moves on heavy volume affect the index more than moves on light volume.
+func VWRSI(close, volume series.Data, period int, adjust bool) (vwrsi series.Data) {
+	var (
+		up   = close.Clone().Diff(1).Mul(volume)
+		down = up.Clone()
+	)
+
+	upValues := up.Values()
+	for i, v := range upValues {
+		if v < 0 {
+			upValues[i] = 0
+		}
+	}
+
+	downValues := down.Values()
+	for i, v := range downValues {
+		if v > 0 {
+			downValues[i] = 0
+		}
+	}
+
+	var (
+		alpha = DType(1.0 / float64(period))
+		gain  = up.EWM(series.Alpha, alpha, adjust, true).Mean()
+		loss  = down.Abs().EWM(series.Alpha, alpha, adjust, true).Mean()
+	)
+
+	vwrsi = relativeStrength(gain, loss)
+
+	return vwrsi
+}
+
+// vwema is the volume-weighted exponential moving average of the price.
+func vwema(price, volume series.Data, period float64, adjust bool) series.Data {
+	var (
+		pv = price.Clone().Mul(volume).EWM(series.AlphaSpan, DType(period), adjust, false).Mean()
+		v  = volume.EWM(series.AlphaSpan, DType(period), adjust, false).Mean()
+	)
+	return div(pv, v)
+}
+
+// FDI is the fractal dimension index by Carlos Sevcik. It estimates fractal dimension of the price curve over the period.
+// Values are between 1 and 2: values near 1 mean the trending market, values near 2 mean the ranging market.
+// 1.5 is the dimension of random walk.
+func FDI(close series.Data, period int) (fdi series.Data) {
+	fdi = close.Rolling(period).Apply(func(window series.Data) DType {
+		return fractalDimension(window.Values())
+	})
+	return fdi
+}
+
+// fractalDimension estimates fractal dimension of values by Sevcik's method:
+// the curve is scaled into the unit square and its length is converted to the dimension.
+func fractalDimension(values []DType) DType {
+	n := len(values)
+	if n < 2 {
+		return math.NaN()
+	}
+
+	var (
+		highest = values[0]
+		lowest  = values[0]
+	)
+
+	for _, v := range values {
+		if series.IsNA(v) {
+			return math.NaN()
+		}
+		if v > highest {
+			highest = v
+		}
+		if v < lowest {
+			lowest = v
+		}
+	}
+
+	var (
+		length DType
+		dx     = 1 / DType(n-1)
+		dy     DType
+		rng    = highest - lowest
+	)
+
+	for i := 1; i < n; i++ {
+		if rng != 0 {
+			dy = (values[i] - values[i-1]) / rng
+		}
+		length += math.Sqrt(dy*dy + dx*dx)
+	}
+
+	return 1 + (math.Log(length)+math.Ln2)/math.Log(2*DType(n-1))
+}
+
+// TR is the true range: the greatest of the current high minus low and distances from the previous close to the current high and low.
+// The true range of the first bar is its high minus low.
+func TR(high, low, close series.Data) (tr series.Data) {
+	var (
+		h = high.Values()
+		l = low.Values()
+		c = close.Values()
+	)
+
+	tr = high.Clone()
+	values := tr.Values()
+
+	for i := range values {
+		values[i] = h[i] - l[i]
+		if i == 0 {
+			continue
+		}
+		values[i] = math.Max(values[i], math.Max(math.Abs(h[i]-c[i-1]), math.Abs(l[i]-c[i-1])))
+	}
+
+	return tr
+}
+
+// ATR is the average true range, Wilder's smoothing of true range over the period exactly as in TA-Lib.
+// It measures volatility including gaps between bars. The first bar has no previous close,
+// so its true range is skipped and the first period values are NaN.
+func ATR(high, low, close series.Data, period int) (atr series.Data) {
+	tr := TR(high, low, close)
+	if tr.Len() > 0 {
+		tr.Values()[0] = math.NaN()
+	}
+
+	atr = RMA(tr, period)
+	return atr
+}
+
+// DMI is the directional movement index of Welles Wilder: +DI and -DI show the part of the true range
+// made by upward and downward price movements. Directional movements and true ranges are smoothed by Wilder's method
+// exactly as in TA-Lib, the first period values are NaN.
+func DMI(high, low, close series.Data, period int) (plusDI, minusDI series.Data) {
+	plusDI, minusDI, _ = directionalMovement(high, low, close, period)
+	return plusDI, minusDI
+}
+
+// ADX is the average directional index, Wilder's smoothing of the spread between +DI and -DI.
+// It measures strength of the trend regardless of its direction. The first 2*period-1 values are NaN.
+func ADX(high, low, close series.Data, period int) (adx series.Data) {
+	_, _, adx = directionalMovement(high, low, close, period)
+	return adx
+}
+
+func directionalMovement(high, low, close series.Data, period int) (plusDI, minusDI, adx series.Data) {
+	var (
+		h  = high.Values()
+		l  = low.Values()
+		tr = TR(high, low, close).Values()
+		n  = DType(period)
+
+		plusDM, minusDM, trueRange DType
+	)
+
+	plusDI = close.Clone()
+	minusDI = close.Clone()
+	adx = close.Clone()
+
+	var (
+		pdi = plusDI.Values()
+		mdi = minusDI.Values()
+		dx  = adx.Values()
+	)
+
+	for i := range tr {
+		pdi[i], mdi[i], dx[i] = math.NaN(), math.NaN(), math.NaN()
+
+		if i == 0 {
+			continue
+		}
+
+		var (
+			up   = h[i] - h[i-1]
+			down = l[i-1] - l[i]
+			pdm  DType
+			mdm  DType
+		)
+
+		if up > down && up > 0 {
+			pdm = up
+		}
+		if down > up && down > 0 {
+			mdm = down
+		}
+
+		if i < period {
+			plusDM += pdm
+			minusDM += mdm
+			trueRange += tr[i]
+			continue
+		}
+
+		plusDM += pdm - plusDM/n
+		minusDM += mdm - minusDM/n
+		trueRange += tr[i] - trueRange/n
+
+		pdi[i] = 100 * divide(plusDM, trueRange)
+		mdi[i] = 100 * divide(minusDM, trueRange)
+
+		dx[i] = 100 * divide(math.Abs(pdi[i]-mdi[i]), pdi[i]+mdi[i])
+	}
+
+	adx = RMA(adx, period)
+
+	return plusDI, minusDI, adx
+}
+
+// CCI is the commodity channel index of Donald Lambert: the deviation of typical price from its simple moving average
+// divided by the constant times the mean absolute deviation over the period. Lambert's constant is 0.015,
+// so about 70-80% of values fall between -100 and +100. The first period-1 values are NaN.
+func CCI(high, low, close series.Data, period int, constant float64) (cci series.Data) {
+	tp := high.Clone().Add(low).Add(close).DivScalar(3)
+
+	var (
+		values = tp.Values()
+		k      = DType(constant)
+	)
+
+	cci = tp.Clone()
+	out := cci.Values()
+
+	for i := range out {
+		if i < period-1 {
+			out[i] = math.NaN()
+			continue
+		}
+
+		mean, dev := meanDeviation(values[i-period+1 : i+1])
+		out[i] = divide(values[i]-mean, k*dev)
+	}
+
+	return cci
+}
+
+// meanDeviation returns the mean and the mean absolute deviation from it.
+func meanDeviation(window []DType) (mean, dev DType) {
+	for _, v := range window {
+		mean += v
+	}
+	mean /= DType(len(window))
+
+	for _, v := range window {
+		dev += math.Abs(v - mean)
+	}
+	dev /= DType(len(window))
+
+	return mean, dev
+}
+
+// DONCHIAN is the Donchian channel: the upper line is the highest high and the lower line is the lowest low over the period,
+// the middle line is halfway between them. The current bar is included, shift lines by one bar to compare them with the close
+// for breakouts like BREAKOUT does. The first period-1 values are NaN.
+func DONCHIAN(high, low series.Data, period int) (upper, lower, middle series.Data) {
+	upper = RollingMax(high, period)
+	lower = RollingMin(low, period)
+	middle = upper.Clone().Add(lower).DivScalar(2)
+	return upper, lower, middle
+}
+
+// VWAP is the rolling volume-weighted average price: the sum of typical price times volume divided by the sum of volume
+// over the period. The first period-1 values are NaN.
+func VWAP(high, low, close, volume series.Data, period int) (vwap series.Data) {
+	tp := high.Clone().Add(low).Add(close).DivScalar(3)
+
+	vwap = div(SMA(tp.Mul(volume), period), SMA(volume, period))
+	return vwap
+}
+
+// SessionVWAP is the volume-weighted average price accumulated from the start of the session.
+// Sessions are intervals of the given length in units of the index, e.g. int64(24*time.Hour) for daily sessions,
+// aligned to the unix epoch shifted by offset, e.g. int64(13*time.Hour + 30*time.Minute) for sessions of NYSE in UTC.
+// The average is reset at the first bar of every session.
+func SessionVWAP(high, low, close, volume series.Data, session, offset int64) (vwap series.Data) {
+	var (
+		h     = high.Values()
+		l     = low.Values()
+		c     = close.Values()
+		v     = volume.Values()
+		index = close.Index()
+
+		pv, vol float64
+		current int64
+	)
+
+	vwap = close.Clone()
+	out := vwap.Values()
+
+	for i, ts := range index {
+		start := sessionStart(ts, session, offset)
+
+		if i == 0 || start != current {
+			current = start
+			pv, vol = 0, 0
+		}
+
+		if !series.IsNA(v[i]) {
+			tp := (h[i] + l[i] + c[i]) / 3
+			pv += float64(tp * v[i])
+			vol += float64(v[i])
+		}
+
+		out[i] = divide(DType(pv), DType(vol))
+	}
+
+	return vwap
+}
+
+// sessionStart returns the start of the session containing ts, sessions are aligned to the epoch shifted by offset.
+func sessionStart(ts, session, offset int64) int64 {
+	start := ts - offset
+	start -= start % session
+	if start > ts-offset {
+		// Floor division for times before the origin.
+		start -= session
+	}
+	return start + offset
+}
+
+// AnchoredVWAP is the volume-weighted average price accumulated from the first bar at or after the anchor timestamp,
+// e.g. a swing low or an earnings bar. Values before the anchor are NaN.
+func AnchoredVWAP(ohlcv OHLCV, anchor int64) (vwap series.Data) {
+	var (
+		h     = ohlcv.High.Values()
+		l     = ohlcv.Low.Values()
+		c     = ohlcv.Close.Values()
+		v     = ohlcv.Volume.Values()
+		index = ohlcv.Close.Index()
+
+		pv, vol float64
+	)
+
+	start := sort.Search(len(index), func(i int) bool { return index[i] >= anchor })
+
+	vwap = ohlcv.Close.Clone()
+	out := vwap.Values()
+
+	for i := range out {
+		if i < start {
+			out[i] = math.NaN()
+			continue
+		}
+
+		if !series.IsNA(v[i]) {
+			tp := (h[i] + l[i] + c[i]) / 3
+			pv += float64(tp * v[i])
+			vol += float64(v[i])
+		}
+
+		out[i] = divide(DType(pv), DType(vol))
+	}
+
+	return vwap
+}
+
+// AROON is the Aroon indicator of Tushar Chande. Aroon up is the percent of the period passed since the highest high
+// within the last period+1 bars, Aroon down is the same for the lowest low, the oscillator is up minus down.
+// Values near 100 mean recent extremes and a strong trend. The latest extreme wins ties like in TA-Lib.
+// The first period values are NaN.
+func AROON(high, low series.Data, period int) (up, down, oscillator series.Data) {
+	var (
+		h = high.Values()
+		l = low.Values()
+		n = DType(period)
+	)
+
+	up = high.Clone()
+	down = high.Clone()
+	oscillator = high.Clone()
+
+	var (
+		u = up.Values()
+		d = down.Values()
+		o = oscillator.Values()
+	)
+
+	for i := range u {
+		if i < period {
+			u[i], d[i], o[i] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+
+		highest, lowest := i-period, i-period
+		for j := i - period + 1; j <= i; j++ {
+			if h[j] >= h[highest] {
+				highest = j
+			}
+			if l[j] <= l[lowest] {
+				lowest = j
+			}
+		}
+
+		u[i] = 100 * (n - DType(i-highest)) / n
+		d[i] = 100 * (n - DType(i-lowest)) / n
+		o[i] = u[i] - d[i]
+	}
+
+	return up, down, oscillator
+}
+
+// WILLIAMS is Williams %R: the position of the close below the highest high of the period relative to the range of the period,
+// scaled to -100..0. It's the stochastic %K turned upside down, values above -20 are overbought and below -80 are oversold.
+// The first period-1 values are NaN.
+func WILLIAMS(high, low, close series.Data, period int) (williams series.Data) {
+	highestHigh := RollingMax(high, period)
+	lowestLow := RollingMin(low, period)
+
+	williams = div(highestHigh.Clone().Sub(close), highestHigh.Sub(lowestLow)).MulScalar(-100)
+	return williams
+}
+
+// DEMA is the double exponential moving average of Patrick Mulloy: 2*EMA - EMA(EMA).
+// It reduces the lag of EMA by subtracting the lag of the double smoothing.
+func DEMA(column series.Data, period int, adjust bool) (dema series.Data) {
+	ema := EMA(column, period, adjust)
+	dema = EMA(ema, period, adjust).MulScalar(-1).Add(ema.MulScalar(2))
+	return dema
+}
+
+// TEMA is the triple exponential moving average of Patrick Mulloy: 3*EMA - 3*EMA(EMA) + EMA(EMA(EMA)).
+// It follows prices closer than DEMA.
+func TEMA(column series.Data, period int, adjust bool) (tema series.Data) {
+	var (
+		ema1 = EMA(column, period, adjust)
+		ema2 = EMA(ema1, period, adjust)
+		ema3 = EMA(ema2, period, adjust)
+	)
+
+	tema = ema1.MulScalar(3).Sub(ema2.MulScalar(3)).Add(ema3)
+	return tema
+}
+
+// KAMA is Kaufman's adaptive moving average. The efficiency ratio is the net price change over erPeriod divided by
+// the sum of absolute changes, it moves the smoothing constant between alphas of fastPeriod and slowPeriod EMAs:
+// the average follows trends quickly and stays flat in noise. Common periods are 10, 2 and 30.
+// The average is seeded by the close at erPeriod-1, the first erPeriod values are NaN.
+func KAMA(column series.Data, erPeriod, fastPeriod, slowPeriod int) (kama series.Data) {
+	var (
+		c    = column.Values()
+		fast = 2 / DType(fastPeriod+1)
+		slow = 2 / DType(slowPeriod+1)
+		prev DType
+	)
+
+	kama = column.Clone()
+	values := kama.Values()
+
+	for i := range values {
+		if i < erPeriod {
+			if i == erPeriod-1 {
+				prev = c[i]
+			}
+			values[i] = math.NaN()
+			continue
+		}
+
+		var volatility DType
+		for j := i - erPeriod + 1; j <= i; j++ {
+			volatility += math.Abs(c[j] - c[j-1])
+		}
+
+		er := divide(math.Abs(c[i]-c[i-erPeriod]), volatility)
+		if series.IsNA(er) {
+			er = 0
+		}
+
+		sc := er*(fast-slow) + slow
+		prev += sc * sc * (c[i] - prev)
+		values[i] = prev
+	}
+
+	return kama
+}
+
+// ZLEMA is the zero-lag exponential moving average of John Ehlers and Ric Way.
+// The lag of EMA is compensated by adding the momentum over (period-1)/2 bars to prices before smoothing.
+// The first (period-1)/2 values are NaN.
+func ZLEMA(column series.Data, period int, adjust bool) (zlema series.Data) {
+	lag := (period - 1) / 2
+
+	zlema = column.Clone()
+	values := zlema.Values()
+
+	if lag >= len(values) {
+		for i := range values {
+			values[i] = math.NaN()
+		}
+		return zlema
+	}
+
+	delagged := column.Clone().MulScalar(2).Sub(column.Clone().Shift(lag))
+	ema := EMA(delagged.Slice(lag, delagged.Len()), period, adjust).Values()
+
+	for i := range values {
+		if i < lag {
+			values[i] = math.NaN()
+		} else {
+			values[i] = ema[i-lag]
+		}
+	}
+
+	return zlema
+}
+
+// VWMA is the volume-weighted moving average: the rolling sum of price times volume divided by the rolling sum of volume.
+// The first period-1 values are NaN.
+func VWMA(price, volume series.Data, period int) (vwma series.Data) {
+	vwma = div(SMA(price.Clone().Mul(volume), period), SMA(volume, period))
+	return vwma
+}
+
+// EVWMA is the elastic volume-weighted moving average of Christian Fries: every bar moves the average towards the price
+// by the share of its volume in the volume of the last period bars, (sum - v) * prev + v * price divided by sum.
+// The average is seeded by the price at period-1, the first period-1 values are NaN.
+func EVWMA(price, volume series.Data, period int) (evwma series.Data) {
+	var (
+		p   = price.Values()
+		v   = volume.Values()
+		vol = SMA(volume, period).MulScalar(DType(period)).Values()
+		avg DType
+	)
+
+	evwma = price.Clone()
+	values := evwma.Values()
+
+	for i := range values {
+		switch {
+		case i < period-1:
+			values[i] = math.NaN()
+			continue
+		case i == period-1:
+			avg = p[i]
+		default:
+			if x := divide((vol[i]-v[i])*avg+v[i]*p[i], vol[i]); !series.IsNA(x) {
+				avg = x
+			}
+		}
+
+		values[i] = avg
+	}
+
+	return evwma
+}
+
+// EBBP is the Elder Ray of Alexander Elder. Bull power is the high minus EMA of the close, it shows the ability of buyers
+// to push prices above the consensus of value. Bear power is the low minus EMA of the close and shows the strength of sellers.
+func EBBP(high, low, close series.Data, period int, adjust bool) (bullPower, bearPower series.Data) {
+	ema := EMA(close, period, adjust)
+
+	bullPower = high.Clone().Sub(ema)
+	bearPower = low.Clone().Sub(ema)
+	return bullPower, bearPower
+}
+
+// EMV is the ease of movement of Richard Arms smoothed by the simple moving average over the period.
+// The distance moved by the midpoint of the bar is divided by the box ratio, the volume in hundreds of millions per unit of the range.
+// Large positive values mean that prices rise easily on low volume. The first period values are NaN.
+func EMV(high, low, volume series.Data, period int) (emv series.Data) {
+	var (
+		h = high.Values()
+		l = low.Values()
+		v = volume.Values()
+	)
+
+	raw := high.Clone()
+	values := raw.Values()
+
+	for i := range values {
+		if i == 0 {
+			values[i] = math.NaN()
+			continue
+		}
+
+		distance := (h[i]+l[i])/2 - (h[i-1]+l[i-1])/2
+		boxRatio := divide(v[i]/1e8, h[i]-l[i])
+
+		values[i] = divide(distance, boxRatio)
+	}
+
+	// The first raw value is unknown, so the first full window ends at period.
+	emv = nanHead(SMA(raw, period), period)
+	return emv
+}
+
+// AO is the awesome oscillator of Bill Williams: the 5 bars simple moving average of the median price
+// minus the 34 bars one. The first 33 values are NaN.
+func AO(high, low series.Data) (ao series.Data) {
+	median := high.Clone().Add(low).DivScalar(2)
+
+	ao = SMA(median, 5).Sub(SMA(median, 34))
+	return ao
+}
+
+// AC is the accelerator oscillator of Bill Williams: AO minus its 5 bars simple moving average.
+// It shows acceleration of the driving force of the market. The first 37 values are NaN.
+func AC(high, low series.Data) (ac series.Data) {
+	ao := AO(high, low)
+
+	ac = nanHead(ao.Clone().Sub(SMA(ao, 5)), 37)
+	return ac
+}
+
+// nanHead sets the first n values of column to NaN in place and returns it.
+// It hides warm-up values averaged over windows which aren't filled yet.
+func nanHead(column series.Data, n int) series.Data {
+	values := column.Values()
+	for i := 0; i < n && i < len(values); i++ {
+		values[i] = math.NaN()
+	}
+	return column
+}
+
+// CMO is the Chande momentum oscillator: the difference of sums of gains and losses over the period
+// divided by their total, scaled to -100..+100. Unlike RSI it uses plain sums, so it reacts to every change equally.
+// The first period values are NaN, flat windows follow DivByZero policy.
+func CMO(close series.Data, period int) (cmo series.Data) {
+	var (
+		c             = close.Values()
+		gains, losses = make([]DType, len(c)), make([]DType, len(c))
+		up, down      windowSum
+	)
+
+	cmo = close.Clone()
+	values := cmo.Values()
+
+	for i := range values {
+		if i > 0 {
+			if change := c[i] - c[i-1]; change > 0 {
+				gains[i] = change
+			} else if change < 0 {
+				losses[i] = -change
+			}
+		}
+
+		up.add(gains[i])
+		down.add(losses[i])
+
+		if i > period {
+			up.sub(gains[i-period])
+			down.sub(losses[i-period])
+		}
+
+		if i < period {
+			values[i] = math.NaN()
+			continue
+		}
+
+		values[i] = 100 * divide(up.sum-down.sum, up.sum+down.sum)
+	}
+
+	return cmo
+}
+
+// windowSum is the running sum of non-negative values of the rolling window.
+// Float additions and subtractions leave a residual when all values leave the window,
+// so the sum is snapped to zero when the window has no positive values.
+type windowSum struct {
+	sum      DType
+	positive int
+}
+
+func (w *windowSum) add(v DType) {
+	if v > 0 {
+		w.sum += v
+		w.positive++
+	}
+}
+
+func (w *windowSum) sub(v DType) {
+	if v > 0 {
+		w.sum -= v
+		w.positive--
+	}
+	if w.positive == 0 {
+		w.sum = 0
+	}
+}
+
+// PVO is the percentage volume oscillator: the difference of fast and slow EMAs of volume in percents of the slow one.
+// Positive values mean that volume is above its average. The signal line is EMA of PVO over the signal period.
+func PVO(volume series.Data, fast, slow, signal int, adjust bool) (pvo, pvoSignal series.Data) {
+	var (
+		emaFast = EMA(volume, fast, adjust)
+		emaSlow = EMA(volume, slow, adjust)
+	)
+
+	pvo = div(emaFast.Sub(emaSlow), emaSlow).MulScalar(100)
+	pvoSignal = EMA(pvo, signal, adjust)
+
+	return pvo, pvoSignal
+}
+
+// WTO is the WaveTrend oscillator of LazyBear. The deviation of typical price from its EMA over channelLen
+// is normalized by 0.015 of EMA of the absolute deviation like CCI, wt1 is its EMA over averageLen
+// and wt2 is the 4 bars simple moving average of wt1. Crosses of wt1 and wt2 beyond ±60 are the usual signals.
+// EMAs aren't adjusted to match TradingView.
+func WTO(high, low, close series.Data, channelLen, averageLen int) (wt1, wt2 series.Data) {
+	var (
+		tp  = high.Clone().Add(low).Add(close).DivScalar(3)
+		esa = EMA(tp, channelLen, false)
+		dev = tp.Clone().Sub(esa)
+		d   = EMA(dev.Clone().Abs(), channelLen, false)
+	)
+
+	ci := div(dev, d.MulScalar(0.015))
+
+	wt1 = EMA(ci, averageLen, false)
+	wt2 = SMA(wt1, 4)
+
+	return wt1, wt2
+}
+
+// MOM is the momentum: the difference of the price and the price period bars ago.
+// Smoothing above 1 smooths momentum by the simple moving average over smoothing bars.
+// The first period+smoothing-1 values are NaN.
+func MOM(close series.Data, period, smoothing int) (mom series.Data) {
+	mom = close.Clone().Diff(period)
+
+	if smoothing > 1 {
+		mom = nanHead(SMA(mom, smoothing), period+smoothing-1)
+	}
+
+	return mom
+}
+
+// BOP is the balance of power of Igor Livshin: the close minus the open divided by the range of the bar,
+// smoothed by the simple moving average over the period. Positive values mean that buyers dominate.
+// The first period-1 values are NaN.
+func BOP(open, high, low, close series.Data, period int) (bop series.Data) {
+	raw := div(close.Clone().Sub(open), high.Clone().Sub(low))
+
+	bop = SMA(raw, period)
+	return bop
+}
+
+// KVO is the Klinger volume oscillator. The volume force is the volume signed by the trend of typical price and
+// scaled by |2*dm/cm - 1|, where dm is the range of the bar and cm is the range accumulated since the trend changed.
+// The oscillator is the fast EMA of volume force minus the slow one, the signal line is EMA of the oscillator.
+// Common periods are 34, 55 and 13. EMAs aren't adjusted.
+func KVO(high, low, close, volume series.Data, fast, slow, signal int) (kvo, kvoSignal series.Data) {
+	var (
+		h = high.Values()
+		l = low.Values()
+		c = close.Values()
+		v = volume.Values()
+
+		trend, prevTrend DType
+		cm, prevDM       DType
+	)
+
+	force := close.Clone()
+	vf := force.Values()
+
+	for i := range vf {
+		dm := h[i] - l[i]
+
+		if i == 0 {
+			vf[i] = 0
+			cm, prevDM = dm, dm
+			continue
+		}
+
+		if (h[i]+l[i]+c[i])/3 > (h[i-1]+l[i-1]+c[i-1])/3 {
+			trend = 1
+		} else {
+			trend = -1
+		}
+
+		if trend == prevTrend {
+			cm += dm
+		} else {
+			cm = prevDM + dm
+		}
+
+		vf[i] = v[i] * math.Abs(2*divide(dm, cm)-1) * trend * 100
+
+		prevTrend, prevDM = trend, dm
+	}
+
+	kvo = EMA(force, fast, false).Sub(EMA(force, slow, false))
+	kvoSignal = EMA(kvo, signal, false)
+
+	return kvo, kvoSignal
+}
+
+// SQUEEZE is the TTM squeeze in LazyBear's Squeeze Momentum form. The squeeze is on (1) when Bollinger Bands
+// of bbPeriod and bbMult deviations are inside Keltner Channels of kcPeriod and kcMult simple averages of true range around SMA,
+// and off (0) otherwise. The momentum is the end of the linear regression over kcPeriod of the close minus
+// the average of the Donchian midline and SMA of the close. Squeezes precede breakouts in the direction of momentum.
+func SQUEEZE(high, low, close series.Data, bbPeriod, kcPeriod int, bbMult, kcMult float64) (squeeze, momentum series.Data) {
+	var (
+		bbUpper, bbLower = BBANDS(close, SMA(close, bbPeriod), bbPeriod, bbMult)
+
+		kcMiddle = SMA(close, kcPeriod)
+		kcRange  = SMA(TR(high, low, close), kcPeriod).MulScalar(DType(kcMult))
+		kcUpper  = kcMiddle.Clone().Add(kcRange)
+		kcLower  = kcMiddle.Clone().Sub(kcRange)
+
+		donchianUpper, donchianLower, _ = DONCHIAN(high, low, kcPeriod)
+	)
+
+	squeeze = close.Clone()
+
+	var (
+		sq = squeeze.Values()
+		bu = bbUpper.Values()
+		bl = bbLower.Values()
+		ku = kcUpper.Values()
+		kl = kcLower.Values()
+	)
+
+	for i := range sq {
+		switch {
+		case i < bbPeriod-1 || i < kcPeriod-1 || series.IsNA(bu[i]) || series.IsNA(ku[i]):
+			sq[i] = math.NaN()
+		case bl[i] > kl[i] && bu[i] < ku[i]:
+			sq[i] = 1
+		default:
+			sq[i] = 0
+		}
+	}
+
+	mid := donchianUpper.Add(donchianLower).DivScalar(2).Add(kcMiddle).DivScalar(2)
+
+	momentum, _, _, _ = LINREG(close.Clone().Sub(mid), kcPeriod)
+
+	return squeeze, momentum
+}
+
+// CHOP is the choppiness index of E.W. Dreiss: the logarithm of the sum of true ranges over the period
+// relative to the range of the period, scaled to 0..100 by the logarithm of the period.
+// Values above 61.8 mean a choppy sideways market, below 38.2 a trending one. The first period-1 values are NaN.
+func CHOP(high, low, close series.Data, period int) (chop series.Data) {
+	var (
+		sum = SMA(TR(high, low, close), period).MulScalar(DType(period))
+		rng = RollingMax(high, period).Sub(RollingMin(low, period))
+	)
+
+	chop = div(sum, rng)
+
+	values := chop.Values()
+	for i, v := range values {
+		values[i] = 100 * math.Log(v) / math.Log(DType(period))
+	}
+
+	return chop
+}
+
+// VHF is the vertical horizontal filter of Adam White: the range of closes over the period divided by
+// the sum of absolute close changes. High values mean a trending market, low values a congestion.
+// The first period values are NaN.
+func VHF(close series.Data, period int) (vhf series.Data) {
+	var (
+		rng     = RollingMax(close, period).Sub(RollingMin(close, period))
+		changes = nanHead(SMA(close.Clone().Diff(1).Abs(), period), period).MulScalar(DType(period))
+	)
+
+	vhf = div(rng, changes)
+	return vhf
+}
+
+// ZIGZAG returns confirmed swing pivots as a sparse series: the high of the swing peak and the low of the swing trough
+// at their bars and NaN elsewhere. A pivot is confirmed when the price reverses from it by deviation,
+// a fraction of the pivot price, e.g. 0.05 is 5%. The last extreme isn't confirmed yet and isn't marked,
+// so pivots never repaint. Pass closes as both high and low for the close-based variant.
+func ZIGZAG(high, low series.Data, deviation float64) (zigzag series.Data) {
+	var (
+		h   = high.Values()
+		l   = low.Values()
+		dev = DType(deviation)
+
+		trend        int
+		peak, trough int
+		peakV, trghV = math.NaN(), math.NaN()
+	)
+
+	zigzag = high.Clone()
+	values := zigzag.Values()
+
+	for i := range values {
+		values[i] = math.NaN()
+
+		if series.IsNA(h[i]) || series.IsNA(l[i]) {
+			continue
+		}
+
+		switch trend {
+		case 0:
+			if series.IsNA(peakV) || h[i] > peakV {
+				peak, peakV = i, h[i]
+			}
+			if series.IsNA(trghV) || l[i] < trghV {
+				trough, trghV = i, l[i]
+			}
+
+			switch {
+			case peak > trough && peakV >= trghV*(1+dev):
+				values[trough] = trghV
+				trend = 1
+			case trough > peak && trghV <= peakV*(1-dev):
+				values[peak] = peakV
+				trend = -1
+			}
+
+		case 1:
+			if h[i] > peakV {
+				peak, peakV = i, h[i]
+			} else if l[i] <= peakV*(1-dev) {
+				values[peak] = peakV
+				trend = -1
+				trough, trghV = i, l[i]
+			}
+
+		case -1:
+			if l[i] < trghV {
+				trough, trghV = i, l[i]
+			} else if h[i] >= trghV*(1+dev) {
+				values[trough] = trghV
+				trend = 1
+				peak, peakV = i, h[i]
+			}
+		}
+	}
+
+	return zigzag
+}
+
+// STARC are Stoller Average Range Channels: bands placed multiplier average true ranges of atrPeriod
+// above and below SMA of smaPeriod of the close. Unlike Bollinger Bands their width follows ranges of bars,
+// so gaps widen them too.
+func STARC(high, low, close series.Data, smaPeriod, atrPeriod int, multiplier float64) (upper, lower series.Data) {
+	var (
+		middle = SMA(close, smaPeriod)
+		rng    = ATR(high, low, close, atrPeriod).MulScalar(DType(multiplier))
+	)
+
+	upper = middle.Clone().Add(rng)
+	lower = middle.Sub(rng)
+
+	return upper, lower
+}
+
+// PSY is the psychological line: the percentage of closes above previous closes over the period.
+// Values above 75 are considered overbought and below 25 oversold. The first period values are NaN.
+func PSY(close series.Data, period int) (psy series.Data) {
+	var (
+		c   = close.Values()
+		ups = make([]int, len(c))
+		n   int
+	)
+
+	psy = close.Clone()
+	values := psy.Values()
+
+	for i := range values {
+		if i > 0 && c[i] > c[i-1] {
+			ups[i] = 1
+		}
+
+		n += ups[i]
+
+		if i > period {
+			n -= ups[i-period]
+		}
+
+		if i < period {
+			values[i] = math.NaN()
+			continue
+		}
+
+		values[i] = 100 * DType(n) / DType(period)
+	}
+
+	return psy
+}
+
+// IMI is the intraday momentum index of Tushar Chande: RSI of intraday moves, the sum of close minus open of up bars
+// in percents of the sum of absolute intraday moves over the period. Values above 70 are considered overbought
+// and below 30 oversold. The first period-1 values are NaN.
+func IMI(open, close series.Data, period int) (imi series.Data) {
+	var (
+		o             = open.Values()
+		c             = close.Values()
+		gains, losses = make([]DType, len(c)), make([]DType, len(c))
+		up, down      windowSum
+	)
+
+	imi = close.Clone()
+	values := imi.Values()
+
+	for i := range values {
+		if move := c[i] - o[i]; move > 0 {
+			gains[i] = move
+		} else if move < 0 {
+			losses[i] = -move
+		}
+
+		up.add(gains[i])
+		down.add(losses[i])
+
+		if i >= period {
+			up.sub(gains[i-period])
+			down.sub(losses[i-period])
+		}
+
+		if i < period-1 {
+			values[i] = math.NaN()
+			continue
+		}
+
+		values[i] = 100 * divide(up.sum, up.sum+down.sum)
+	}
+
+	return imi
+}
@@ -0,0 +1,454 @@
+package fta
+
+import (
+	stdmath "math"
+	"testing"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+var nan = math.NaN()
+
+// divPolicies are all policies of division by zero.
+var divPolicies = []DivPolicy{DivNaN, DivZero, DivEpsilon, DivIEEE}
+
+// makeData returns the series of values with the index 0, 1, 2...
+func makeData(values ...DType) series.Data {
+	index := make([]int64, len(values))
+	for i := range index {
+		index[i] = int64(i)
+	}
+	return series.MakeData(1, index, append([]DType(nil), values...))
+}
+
+// withDivPolicy runs fn with DivByZero set to the policy.
+func withDivPolicy(policy DivPolicy, fn func()) {
+	defer func(prev DivPolicy) { DivByZero = prev }(DivByZero)
+	DivByZero = policy
+	fn()
+}
+
+// assertValues fails the test when got differs from want, NaN values are equal.
+func assertValues(t *testing.T, name string, got series.Data, want []DType) {
+	t.Helper()
+
+	values := got.Values()
+	if len(values) != len(want) {
+		t.Fatalf("%s: got %d values, want %d", name, len(values), len(want))
+	}
+
+	for i, w := range want {
+		g := values[i]
+		if series.IsNA(g) && series.IsNA(w) {
+			continue
+		}
+		if series.IsNA(g) || series.IsNA(w) || math.Abs(g-w) > 1e-4*math.Max(1, math.Abs(w)) {
+			t.Fatalf("%s: at %d got %v, want %v\ngot:  %v\nwant: %v", name, i, g, w, values, want)
+		}
+	}
+}
+
+func TestDONCHIAN(t *testing.T) {
+	tests := []struct {
+		name                 string
+		high, low            []DType
+		period               int
+		upper, lower, middle []DType
+	}{
+		{
+			name:   "window",
+			high:   []DType{3, 5, 4, 6, 2},
+			low:    []DType{1, 2, 0, 3, 1},
+			period: 3,
+			upper:  []DType{nan, nan, 5, 6, 6},
+			lower:  []DType{nan, nan, 0, 0, 0},
+			middle: []DType{nan, nan, 2.5, 3, 3},
+		},
+		{
+			name:   "single bar",
+			high:   []DType{3, 5, 4},
+			low:    []DType{1, 2, 0},
+			period: 1,
+			upper:  []DType{3, 5, 4},
+			lower:  []DType{1, 2, 0},
+			middle: []DType{2, 3.5, 2},
+		},
+		{
+			name:   "warm-up longer than data",
+			high:   []DType{3, 5},
+			low:    []DType{1, 2},
+			period: 3,
+			upper:  []DType{nan, nan},
+			lower:  []DType{nan, nan},
+			middle: []DType{nan, nan},
+		},
+		{
+			name:   "shorter than period",
+			high:   []DType{3},
+			low:    []DType{1},
+			period: 3,
+			upper:  []DType{nan},
+			lower:  []DType{nan},
+			middle: []DType{nan},
+		},
+		{
+			name:   "empty",
+			period: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upper, lower, middle := DONCHIAN(makeData(tt.high...), makeData(tt.low...), tt.period)
+
+			assertValues(t, "upper", upper, tt.upper)
+			assertValues(t, "lower", lower, tt.lower)
+			assertValues(t, "middle", middle, tt.middle)
+		})
+	}
+}
+
+func TestDONCHIANFlat(t *testing.T) {
+	for _, policy := range divPolicies {
+		withDivPolicy(policy, func() {
+			upper, lower, middle := DONCHIAN(makeData(2, 2, 2, 2), makeData(2, 2, 2, 2), 2)
+
+			want := []DType{nan, 2, 2, 2}
+			assertValues(t, "upper", upper, want)
+			assertValues(t, "lower", lower, want)
+			assertValues(t, "middle", middle, want)
+		})
+	}
+}
+
+func TestWILLIAMS(t *testing.T) {
+	tests := []struct {
+		name             string
+		high, low, close []DType
+		period           int
+		want             []DType
+	}{
+		{
+			name:   "window",
+			high:   []DType{3, 5, 4, 6, 2},
+			low:    []DType{1, 2, 0, 3, 1},
+			close:  []DType{2, 4, 1, 5, 2},
+			period: 3,
+			want:   []DType{nan, nan, -80, -100.0 / 6, -400.0 / 6},
+		},
+		{
+			name:   "close at extremes",
+			high:   []DType{4, 5, 6},
+			low:    []DType{2, 1, 3},
+			close:  []DType{4, 1, 6},
+			period: 2,
+			want:   []DType{nan, -100, 0},
+		},
+		{
+			name:   "shorter than period",
+			high:   []DType{3, 5},
+			low:    []DType{1, 2},
+			close:  []DType{2, 4},
+			period: 3,
+			want:   []DType{nan, nan},
+		},
+		{
+			name:   "empty",
+			period: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WILLIAMS(makeData(tt.high...), makeData(tt.low...), makeData(tt.close...), tt.period)
+			assertValues(t, "williams", got, tt.want)
+		})
+	}
+}
+
+func TestWILLIAMSFlat(t *testing.T) {
+	// The range of flat windows is zero, so %R is 0/0.
+	tests := []struct {
+		name   string
+		policy DivPolicy
+		want   []DType
+	}{
+		{"nan", DivNaN, []DType{nan, nan, nan, -50}},
+		{"zero", DivZero, []DType{nan, 0, 0, -50}},
+		{"epsilon", DivEpsilon, []DType{nan, 0, 0, -50}},
+		{"ieee", DivIEEE, []DType{nan, nan, nan, -50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withDivPolicy(tt.policy, func() {
+				got := WILLIAMS(makeData(2, 2, 2, 3), makeData(2, 2, 2, 1), makeData(2, 2, 2, 2), 2)
+				assertValues(t, "williams", got, tt.want)
+			})
+		})
+	}
+}
+
+func TestRollingQuantile(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []DType
+		period int
+		q      DType
+		want   []DType
+	}{
+		{
+			name:   "median",
+			values: []DType{3, 1, 4, 1, 5, 9},
+			period: 3,
+			q:      0.5,
+			want:   []DType{nan, nan, 3, 1, 4, 5},
+		},
+		{
+			name:   "interpolated",
+			values: []DType{3, 1, 4, 1, 5, 9},
+			period: 3,
+			q:      0.25,
+			want:   []DType{nan, nan, 2, 1, 2.5, 3},
+		},
+		{
+			name:   "period length",
+			values: []DType{3, 1, 4},
+			period: 3,
+			q:      0.5,
+			want:   []DType{nan, nan, 3},
+		},
+		{
+			name:   "shorter than period",
+			values: []DType{3, 1},
+			period: 5,
+			q:      0.5,
+			want:   []DType{nan, nan},
+		},
+		{
+			name:   "empty",
+			period: 5,
+			q:      0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RollingQuantile(makeData(tt.values...), tt.period, tt.q)
+			assertValues(t, "quantile", got, tt.want)
+		})
+	}
+}
+
+func TestSMMShort(t *testing.T) {
+	assertValues(t, "smm", SMM(makeData(3, 1), 5), []DType{nan, nan})
+}
+
+func TestRemoveOutliers(t *testing.T) {
+	prices := []DType{10, 10.1, 9.9, 10, 50, 10.1, 9.9, 10, 10.1, 9.9}
+	ohlcv := OHLCV{
+		Open:   makeData(prices...),
+		High:   makeData(prices...),
+		Low:    makeData(prices...),
+		Close:  makeData(prices...),
+		Volume: makeData(prices...),
+	}
+
+	got := ohlcv.RemoveOutliers(OutlierMAD, 5, 3)
+
+	want := append([]DType(nil), prices...)
+	want[4] = 10.05
+	assertValues(t, "close", got.Close, want)
+	assertValues(t, "volume", got.Volume, prices)
+}
+
+func TestRemoveOutliersNaN(t *testing.T) {
+	ohlcv := OHLCV{
+		Open:   makeData(nan, nan, nan),
+		High:   makeData(nan, nan, nan),
+		Low:    makeData(nan, nan, nan),
+		Close:  makeData(nan, nan, nan),
+		Volume: makeData(1, 2, 3),
+	}
+
+	got := ohlcv.RemoveOutliers(OutlierZScore, 3, 2)
+
+	assertValues(t, "close", got.Close, []DType{nan, nan, nan})
+	assertValues(t, "volume", got.Volume, []DType{1, 2, 3})
+}
+
+func TestDecompose(t *testing.T) {
+	trend, seasonal, residual := Decompose(makeData(1, 3, 1, 3, 1, 3), 2)
+
+	assertValues(t, "trend", trend, []DType{nan, 2, 2, 2, 2, nan})
+	assertValues(t, "seasonal", seasonal, []DType{-1, 1, -1, 1, -1, 1})
+	assertValues(t, "residual", residual, []DType{nan, 0, 0, 0, 0, nan})
+
+	for _, period := range []int{0, -1} {
+		trend, seasonal, residual := Decompose(makeData(1, 3, 1), period)
+
+		want := []DType{nan, nan, nan}
+		assertValues(t, "trend", trend, want)
+		assertValues(t, "seasonal", seasonal, want)
+		assertValues(t, "residual", residual, want)
+	}
+}
+
+func TestRSIWilderFlat(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy DivPolicy
+		want   []DType
+	}{
+		{"nan", DivNaN, []DType{nan, nan, nan, nan, 100}},
+		{"zero", DivZero, []DType{nan, nan, 0, 0, 100}},
+		{"epsilon", DivEpsilon, []DType{nan, nan, 0, 0, 100}},
+		{"ieee", DivIEEE, []DType{nan, nan, nan, nan, 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withDivPolicy(tt.policy, func() {
+				got := RSIWilder(makeData(1, 1, 1, 1, 2), 2)
+				assertValues(t, "rsi", got, tt.want)
+			})
+		})
+	}
+}
+
+func TestCMOFlat(t *testing.T) {
+	// Running sums of gains and losses must drop to exact zero when the changes leave the window.
+	close := makeData(0.1, 0.7, 0.3, 1.9, 0.2, 0.2, 0.2, 0.2)
+
+	tests := []struct {
+		name   string
+		policy DivPolicy
+		flat   DType
+	}{
+		{"nan", DivNaN, nan},
+		{"zero", DivZero, 0},
+		{"epsilon", DivEpsilon, 0},
+		{"ieee", DivIEEE, nan},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withDivPolicy(tt.policy, func() {
+				got := CMO(close, 3)
+				assertValues(t, "cmo", got, []DType{nan, nan, nan, 100 * 1.8 / 2.6, -100 * 0.5 / 3.7, -100 * 0.1 / 3.3, -100, tt.flat})
+			})
+		})
+	}
+}
+
+func TestIMIFlat(t *testing.T) {
+	// Running sums of intraday moves must drop to exact zero when the moves leave the window.
+	open := makeData(0.1, 0.7, 1.9, 0.2, 0.2, 0.2)
+	close := makeData(0.7, 0.3, 0.2, 0.2, 0.2, 0.2)
+
+	tests := []struct {
+		name   string
+		policy DivPolicy
+		flat   DType
+	}{
+		{"nan", DivNaN, nan},
+		{"zero", DivZero, 0},
+		{"epsilon", DivEpsilon, 0},
+		{"ieee", DivIEEE, nan},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withDivPolicy(tt.policy, func() {
+				got := IMI(open, close, 3)
+				assertValues(t, "imi", got, []DType{nan, nan, 100 * 0.6 / 2.7, 0, 0, tt.flat})
+			})
+		})
+	}
+}
+
+func TestCRSI(t *testing.T) {
+	// Components are RSI of prices, RSI of up/down streaks and ROC, the first RSI values are 0/0.
+	tests := []struct {
+		name   string
+		policy DivPolicy
+		close  []DType
+		want   []DType
+	}{
+		{"rising nan", DivNaN, []DType{1, 2, 3, 4}, []DType{nan, 100, 250.0 / 3, 700.0 / 9}},
+		{"rising zero", DivZero, []DType{1, 2, 3, 4}, []DType{0, 100, 250.0 / 3, 700.0 / 9}},
+		{"flat nan", DivNaN, []DType{1, 1, 1, 1}, []DType{nan, nan, nan, nan}},
+		{"flat zero", DivZero, []DType{1, 1, 1, 1}, []DType{0, 0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withDivPolicy(tt.policy, func() {
+				close := makeData(tt.close...)
+				got := CRSI(close, 2, 2, 1, false)
+				assertValues(t, "crsi", got, tt.want)
+				assertValues(t, "close", close, tt.close)
+			})
+		})
+	}
+}
+
+func TestPctChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		period int
+		want   []DType
+	}{
+		{"previous", 1, []DType{nan, 1, 0.5, -0.5}},
+		{"two back", 2, []DType{nan, nan, 2, -0.25}},
+		{"next", -1, []DType{-0.5, -1.0 / 3, 1, nan}},
+		{"longer than data", -5, []DType{nan, nan, nan, nan}},
+		{"zero", 0, []DType{0, 0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValues(t, "pct", PctChange(makeData(1, 2, 3, 1.5), tt.period), tt.want)
+		})
+	}
+}
+
+func TestDropna(t *testing.T) {
+	inf := DType(stdmath.Inf(1))
+
+	got := Dropna(makeData(1, nan, 2, inf, -inf, 3))
+	assertValues(t, "values", got, []DType{1, 2, 3})
+	if index := got.Index(); len(index) != 3 || index[0] != 0 || index[1] != 2 || index[2] != 5 {
+		t.Fatalf("got index %v, want [0 2 5]", index)
+	}
+
+	got = Dropna(series.MakeValues([]DType{nan, 1, inf, 2}))
+	assertValues(t, "values without index", got, []DType{1, 2})
+	if got.Index() != nil {
+		t.Fatalf("got index %v, want nil", got.Index())
+	}
+}
+
+func TestProgressTracker(t *testing.T) {
+	var reports []Progress
+
+	tracker := NewProgressTracker(func(p Progress) { reports = append(reports, p) }, 1000)
+	for i := 0; i < 1000; i++ {
+		tracker.Add(1)
+	}
+	tracker.Finish()
+
+	// Adds are faster than the interval, so only the final report is made.
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want 1: %v", len(reports), reports)
+	}
+	if p := reports[0]; p.Done != 1000 || p.Total != 1000 || p.Percent() != 100 {
+		t.Fatalf("got final report %+v", p)
+	}
+
+	var disabled *ProgressTracker
+	disabled.Add(1)
+	disabled.Finish()
+	NewProgressTracker(nil, 10).Add(1)
+}
@@ -0,0 +1,171 @@
+package fta
+
+import (
+	stdmath "math"
+	"math/rand"
+	"time"
+)
+
+// Model is the stochastic model of synthetic prices.
+type Model int
+
+const (
+	// GBM is the geometric Brownian motion with constant drift and volatility.
+	GBM Model = iota
+	// Heston is the stochastic volatility model: the variance reverts to its long-run mean
+	// and its shocks are correlated with price shocks.
+	Heston
+	// RegimeSwitching switches between regimes of different drift and volatility at random bars.
+	RegimeSwitching
+)
+
+// Regime is the drift and volatility of prices in a market regime.
+type Regime struct {
+	Drift, Volatility float64
+}
+
+// GenerateParams are parameters of synthetic data. Zero values are replaced by defaults.
+// Drift and volatility are per bar, e.g. 0.01 volatility means 1% standard deviation of bar returns.
+type GenerateParams struct {
+	// Start is the time of the first bar, default is 2000-01-01 UTC.
+	Start time.Time
+	// Freq is the interval between bars, default is 24 hours.
+	Freq time.Duration
+	// Price is the open of the first bar, default is 100.
+	Price float64
+	// Drift is the expected log return per bar of GBM and Heston models.
+	Drift float64
+	// Volatility is the standard deviation of log returns per bar, default is 0.01.
+	// It's the initial and long-run volatility of Heston model.
+	Volatility float64
+	// Volume is the mean volume of bars, default is 1e6.
+	Volume float64
+	// Steps is the number of simulated steps inside every bar to build high and low, default is 32.
+	Steps int
+
+	// MeanReversion is the speed of variance reversion per bar of Heston model, default is 0.05.
+	MeanReversion float64
+	// VolOfVol is the volatility of variance of Heston model, default is half of Volatility.
+	VolOfVol float64
+	// Correlation is the correlation of price and variance shocks of Heston model.
+	Correlation float64
+
+	// Regimes of RegimeSwitching model, default are calm uptrend and volatile downtrend.
+	Regimes []Regime
+	// SwitchProbability is the probability per bar to switch to another random regime, default is 0.02.
+	SwitchProbability float64
+}
+
+func (p GenerateParams) withDefaults() GenerateParams {
+	if p.Start.IsZero() {
+		p.Start = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if p.Freq <= 0 {
+		p.Freq = 24 * time.Hour
+	}
+	if p.Price <= 0 {
+		p.Price = 100
+	}
+	if p.Volatility <= 0 {
+		p.Volatility = 0.01
+	}
+	if p.Volume <= 0 {
+		p.Volume = 1e6
+	}
+	if p.Steps <= 0 {
+		p.Steps = 32
+	}
+	if p.MeanReversion <= 0 {
+		p.MeanReversion = 0.05
+	}
+	if p.VolOfVol <= 0 {
+		p.VolOfVol = p.Volatility / 2
+	}
+	if len(p.Regimes) == 0 {
+		p.Regimes = []Regime{
+			{Drift: 0.0005, Volatility: p.Volatility},
+			{Drift: -0.001, Volatility: 2 * p.Volatility},
+		}
+	}
+	if p.SwitchProbability <= 0 {
+		p.SwitchProbability = 0.02
+	}
+	return p
+}
+
+// Generate returns n bars of synthetic ohlcv data of the model.
+// Every bar is simulated by params.Steps steps of the model, so high and low are extremes of the path inside the bar.
+// Volume is log-normal and grows with the absolute return of the bar.
+// The same seed gives the same data.
+func Generate(model Model, params GenerateParams, n int, seed int64) OHLCV {
+	params = params.withDefaults()
+
+	var (
+		rnd     = rand.New(rand.NewSource(seed))
+		builder = newOHLCVBuilder(n)
+		dt      = 1 / float64(params.Steps)
+
+		price    = params.Price
+		variance = params.Volatility * params.Volatility
+		regime   = 0
+	)
+
+	for i := 0; i < n; i++ {
+		if model == RegimeSwitching && len(params.Regimes) > 1 && rnd.Float64() < params.SwitchProbability {
+			// Pick one of the other regimes.
+			next := rnd.Intn(len(params.Regimes) - 1)
+			if next >= regime {
+				next++
+			}
+			regime = next
+		}
+
+		var (
+			open = price
+			high = price
+			low  = price
+		)
+
+		for s := 0; s < params.Steps; s++ {
+			var (
+				z     = rnd.NormFloat64()
+				drift = params.Drift
+				vol   = params.Volatility
+			)
+
+			switch model {
+			case Heston:
+				// Full truncation keeps the variance process defined when it goes below zero.
+				v := stdmath.Max(variance, 0)
+				vol = stdmath.Sqrt(v)
+
+				zv := params.Correlation*z + stdmath.Sqrt(1-params.Correlation*params.Correlation)*rnd.NormFloat64()
+				variance += params.MeanReversion*(params.Volatility*params.Volatility-v)*dt + params.VolOfVol*vol*stdmath.Sqrt(dt)*zv
+			case RegimeSwitching:
+				drift = params.Regimes[regime].Drift
+				vol = params.Regimes[regime].Volatility
+			}
+
+			price *= stdmath.Exp((drift-vol*vol/2)*dt + vol*stdmath.Sqrt(dt)*z)
+
+			high = stdmath.Max(high, price)
+			low = stdmath.Min(low, price)
+		}
+
+		var (
+			ret    = stdmath.Abs(stdmath.Log(price / open))
+			volume = params.Volume * stdmath.Exp(0.5*rnd.NormFloat64()-0.125) * (1 + ret/params.Volatility) / 2
+		)
+
+		builder.append(Bar{
+			Time:   params.Start.Add(time.Duration(i) * params.Freq).UnixNano(),
+			Open:   DType(open),
+			High:   DType(high),
+			Low:    DType(low),
+			Close:  DType(price),
+			Volume: DType(volume),
+		})
+	}
+
+	return builder.build(int64(params.Freq))
+}
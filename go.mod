@@ -2,7 +2,11 @@ module github.com/WinPooh32/fta
 
 go 1.17
 
-require github.com/WinPooh32/series v0.6.0
+require (
+	github.com/WinPooh32/series v0.6.0
+	github.com/klauspost/compress v1.15.15
+	gonum.org/v1/gonum v0.11.0
+)
 
 require (
 	github.com/WinPooh32/math v1.0.5 // indirect
@@ -0,0 +1,100 @@
+package fta
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/WinPooh32/series"
+)
+
+// SeriesJSON encodes series data to JSON suitable for web charting libraries.
+// By default the series is encoded as object of timestamps and values: {"t":[...],"v":[...]}.
+// NaN values are encoded as null.
+type SeriesJSON struct {
+	Data series.Data
+	// Unit is the unit of encoded timestamps, AutoTime isn't allowed.
+	Unit UnixTime
+	// Pairs encodes series as array of [time, value] pairs.
+	Pairs bool
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (s SeriesJSON) MarshalJSON() ([]byte, error) {
+	var div int64
+
+	switch s.Unit {
+	case Seconds:
+		div = int64(time.Second)
+	case Milliseconds:
+		div = int64(time.Millisecond)
+	case Microseconds:
+		div = int64(time.Microsecond)
+	case Nanoseconds:
+		div = 1
+	default:
+		return nil, errors.New("series json: unsupported time unit")
+	}
+
+	var (
+		index  = s.Data.Index()
+		values = s.Data.Values()
+		buf    bytes.Buffer
+		tmp    []byte
+
+		bitSize = 64
+	)
+
+	if series.EnabledFloat32 {
+		bitSize = 32
+	}
+
+	appendValue := func(v DType) {
+		if series.IsNA(v) {
+			buf.WriteString("null")
+			return
+		}
+		tmp = strconv.AppendFloat(tmp[:0], float64(v), 'g', -1, bitSize)
+		buf.Write(tmp)
+	}
+
+	appendTime := func(ts int64) {
+		tmp = strconv.AppendInt(tmp[:0], ts/div, 10)
+		buf.Write(tmp)
+	}
+
+	if s.Pairs {
+		buf.WriteByte('[')
+		for i, ts := range index {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('[')
+			appendTime(ts)
+			buf.WriteByte(',')
+			appendValue(values[i])
+			buf.WriteByte(']')
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+	}
+
+	buf.WriteString(`{"t":[`)
+	for i, ts := range index {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		appendTime(ts)
+	}
+	buf.WriteString(`],"v":[`)
+	for i, v := range values {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		appendValue(v)
+	}
+	buf.WriteString(`]}`)
+
+	return buf.Bytes(), nil
+}
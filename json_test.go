@@ -0,0 +1,80 @@
+package fta
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/WinPooh32/series"
+)
+
+func TestSeriesJSON(t *testing.T) {
+	start := time.Date(2023, 1, 2, 3, 4, 0, 0, time.UTC).UnixNano()
+
+	data := series.MakeData(int64(time.Minute),
+		[]int64{start, start + int64(time.Minute), start + int64(2*time.Minute)},
+		[]DType{1.5, nan, -2},
+	)
+
+	tests := []struct {
+		name string
+		s    SeriesJSON
+		want string
+	}{
+		{"seconds", SeriesJSON{Data: data, Unit: Seconds}, `{"t":[1672628640,1672628700,1672628760],"v":[1.5,null,-2]}`},
+		{"milliseconds", SeriesJSON{Data: data, Unit: Milliseconds}, `{"t":[1672628640000,1672628700000,1672628760000],"v":[1.5,null,-2]}`},
+		{"pairs", SeriesJSON{Data: data, Unit: Seconds, Pairs: true}, `[[1672628640,1.5],[1672628700,null],[1672628760,-2]]`},
+		{"empty", SeriesJSON{Data: series.MakeData(1, []int64{}, []DType{}), Unit: Nanoseconds}, `{"t":[],"v":[]}`},
+		{"empty pairs", SeriesJSON{Data: series.MakeData(1, []int64{}, []DType{}), Unit: Nanoseconds, Pairs: true}, `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Nested into another value like the chart payload.
+			got, err := json.Marshal(map[string]SeriesJSON{"rsi": tt.s})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := `{"rsi":` + tt.want + `}`; string(got) != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		})
+	}
+
+	// Decoded values give the series back.
+	b, err := json.Marshal(SeriesJSON{Data: data, Unit: Nanoseconds})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		T []int64    `json:"t"`
+		V []*float64 `json:"v"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	values := make([]DType, len(decoded.V))
+	for i, v := range decoded.V {
+		values[i] = nan
+		if v != nil {
+			values[i] = DType(*v)
+		}
+	}
+
+	for i, ts := range data.Index() {
+		if decoded.T[i] != ts {
+			t.Fatalf("time %d: got %d, want %d", i, decoded.T[i], ts)
+		}
+	}
+	assertValues(t, "decoded", series.MakeData(data.Freq(), decoded.T, values), data.Values())
+}
+
+func TestSeriesJSONUnit(t *testing.T) {
+	for _, unit := range []UnixTime{AutoTime, UnixTime(100)} {
+		if _, err := json.Marshal(SeriesJSON{Data: makeData(1), Unit: unit}); err == nil {
+			t.Errorf("unit %d: got no error", unit)
+		}
+	}
+}
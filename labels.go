@@ -0,0 +1,133 @@
+package fta
+
+import (
+	stdmath "math"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// FixedHorizonLabels labels every bar by the forward return of the close over the horizon:
+// 1 when the return is above threshold, -1 when it's below -threshold and 0 otherwise.
+// The last horizon values are NaN because their future is unknown.
+func FixedHorizonLabels(close series.Data, horizon int, threshold float64) (labels, returns series.Data) {
+	var (
+		c   = close.Values()
+		thr = DType(threshold)
+	)
+
+	returns = close.Clone()
+	labels = close.Clone()
+
+	r := returns.Values()
+	l := labels.Values()
+
+	for i := range c {
+		if i+horizon >= len(c) {
+			r[i] = math.NaN()
+			l[i] = math.NaN()
+			continue
+		}
+
+		r[i] = c[i+horizon]/c[i] - 1
+
+		switch {
+		case series.IsNA(r[i]):
+			l[i] = math.NaN()
+		case r[i] > thr:
+			l[i] = 1
+		case r[i] < -thr:
+			l[i] = -1
+		default:
+			l[i] = 0
+		}
+	}
+
+	return labels, returns
+}
+
+// Barriers are parameters of triple-barrier labeling.
+type Barriers struct {
+	// ProfitTarget and StopLoss are distances of the upper and the lower barriers from the entry close
+	// as fractions of the price, e.g. 0.02 is 2%. Zero disables the barrier.
+	ProfitTarget, StopLoss float64
+	// Timeout is the maximal number of bars to hold, the vertical barrier. Zero disables the barrier,
+	// so positions are held until the profit target or the stop loss is touched.
+	Timeout int
+	// ATRPeriod scales ProfitTarget and StopLoss by ATR of the period at the entry bar instead of the price,
+	// e.g. ProfitTarget 2 is 2 ATRs. Zero disables scaling.
+	ATRPeriod int
+}
+
+// TripleBarrierLabels labels every bar by the first barrier touched after the entry at its close:
+// 1 for the profit target, -1 for the stop loss and 0 for the timeout.
+// High and low of bars are checked against barriers, when both are touched by the same bar the stop loss is assumed.
+// Returns are realized at the touched barrier or at the close of the timeout bar, holding is the number of bars to the exit.
+// Values are NaN when the data ends before any barrier is touched.
+func TripleBarrierLabels(ohlcv OHLCV, barriers Barriers) (labels, returns, holding series.Data) {
+	var (
+		h = ohlcv.High.Values()
+		l = ohlcv.Low.Values()
+		c = ohlcv.Close.Values()
+
+		atr []DType
+	)
+
+	if barriers.ATRPeriod > 0 {
+		atr = ATR(ohlcv.High, ohlcv.Low, ohlcv.Close, barriers.ATRPeriod).Values()
+	}
+
+	labels = ohlcv.Close.Clone()
+	returns = ohlcv.Close.Clone()
+	holding = ohlcv.Close.Clone()
+
+	var (
+		lab = labels.Values()
+		ret = returns.Values()
+		hld = holding.Values()
+	)
+
+	for i, entry := range c {
+		lab[i], ret[i], hld[i] = math.NaN(), math.NaN(), math.NaN()
+
+		scale := entry
+		if atr != nil {
+			scale = atr[i]
+		}
+
+		if series.IsNA(entry) || series.IsNA(scale) {
+			continue
+		}
+
+		var (
+			upper = DType(stdmath.Inf(1))
+			lower = DType(stdmath.Inf(-1))
+		)
+
+		if barriers.ProfitTarget > 0 {
+			upper = entry + DType(barriers.ProfitTarget)*scale
+		}
+		if barriers.StopLoss > 0 {
+			lower = entry - DType(barriers.StopLoss)*scale
+		}
+
+		for j := i + 1; j < len(c) && (barriers.Timeout <= 0 || j-i <= barriers.Timeout); j++ {
+			switch {
+			case l[j] <= lower:
+				lab[i], ret[i] = -1, lower/entry-1
+			case h[j] >= upper:
+				lab[i], ret[i] = 1, upper/entry-1
+			case j-i == barriers.Timeout:
+				lab[i], ret[i] = 0, c[j]/entry-1
+			default:
+				continue
+			}
+
+			hld[i] = DType(j - i)
+
+			break
+		}
+	}
+
+	return labels, returns, holding
+}
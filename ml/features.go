@@ -0,0 +1,132 @@
+// Package ml assembles indicators of fta package into feature matrices for machine learning.
+package ml
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+	"gonum.org/v1/gonum/mat"
+)
+
+// IndicatorSpec computes feature columns from ohlcv.
+type IndicatorSpec struct {
+	// Names are names of columns in order of outputs of Compute.
+	Names []string
+	// Compute returns outputs of the indicator, they are aligned to the index of close by timestamps.
+	Compute func(ohlcv fta.OHLCV) []series.Data
+}
+
+// Feature returns the spec of single column indicator.
+func Feature(name string, compute func(ohlcv fta.OHLCV) series.Data) IndicatorSpec {
+	return IndicatorSpec{
+		Names: []string{name},
+		Compute: func(ohlcv fta.OHLCV) []series.Data {
+			return []series.Data{compute(ohlcv)}
+		},
+	}
+}
+
+// Features is the feature matrix: a row per bar and a column per feature.
+type Features struct {
+	// Names are names of columns.
+	Names []string
+	// Index are timestamps of rows in nanoseconds since epoch.
+	Index []int64
+	// Matrix holds values of features, it's nil when there are no rows.
+	Matrix *mat.Dense
+}
+
+// FeatureMatrix computes indicators of specs, aligns their outputs to timestamps of ohlcv
+// and drops rows with NaN values, e.g. warm-up periods of indicators.
+// Ohlcv is cloned before every computation, so indicators may modify it in place.
+func FeatureMatrix(ohlcv fta.OHLCV, specs ...IndicatorSpec) (features Features, err error) {
+	var (
+		index   = ohlcv.Close.Index()
+		columns [][]fta.DType
+	)
+
+	for _, spec := range specs {
+		outputs := spec.Compute(ohlcv.Clone())
+
+		if len(outputs) != len(spec.Names) {
+			return Features{}, fmt.Errorf("feature matrix: %v: got %d outputs, want %d", spec.Names, len(outputs), len(spec.Names))
+		}
+
+		for _, out := range outputs {
+			columns = append(columns, fta.Reindex(out, ohlcv.Close, fta.FillNone).Values())
+		}
+
+		features.Names = append(features.Names, spec.Names...)
+	}
+
+	var (
+		rows []int
+		data []float64
+	)
+
+	for i := range index {
+		complete := true
+		for _, column := range columns {
+			if series.IsNA(column[i]) {
+				complete = false
+				break
+			}
+		}
+
+		if !complete {
+			continue
+		}
+
+		rows = append(rows, i)
+		for _, column := range columns {
+			data = append(data, float64(column[i]))
+		}
+	}
+
+	features.Index = make([]int64, len(rows))
+	for r, i := range rows {
+		features.Index[r] = index[i]
+	}
+
+	if len(rows) > 0 && len(columns) > 0 {
+		features.Matrix = mat.NewDense(len(rows), len(columns), data)
+	}
+
+	return features, nil
+}
+
+// WriteCSV writes features to w as csv with header. The first column is the time in RFC 3339 format in UTC.
+func (f Features) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := append([]string{"time"}, f.Names...)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("write features: %w", err)
+	}
+
+	record := make([]string, len(header))
+
+	for r, ts := range f.Index {
+		record[0] = time.Unix(0, ts).UTC().Format(time.RFC3339Nano)
+		for c := range f.Names {
+			record[c+1] = strconv.FormatFloat(f.Matrix.At(r, c), 'g', -1, 64)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("write features: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("write features: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// SMAMulti returns simple moving averages of column for every period of periods in a single pass.
+// Windows share prefix sums of the column, so the cost doesn't depend on periods values.
+// Results may differ from SMA in the last digits because of the different summation order.
+func SMAMulti(column series.Data, periods []int) (smas []series.Data) {
+	var (
+		values = column.Values()
+		sums   = make([]float64, len(values)+1)
+		counts = make([]int, len(values)+1)
+	)
+
+	for i, v := range values {
+		sums[i+1], counts[i+1] = sums[i], counts[i]
+		if !series.IsNA(v) {
+			sums[i+1] += float64(v)
+			counts[i+1]++
+		}
+	}
+
+	smas = make([]series.Data, len(periods))
+
+	for k, period := range periods {
+		// Keep the series.Window behaviour for the series not longer than the period.
+		if len(values) <= period {
+			smas[k] = SMA(column, period)
+			continue
+		}
+
+		sma := column.Clone()
+		dst := sma.Values()
+
+		for i := range dst {
+			if i < period-1 {
+				dst[i] = math.NaN()
+				continue
+			}
+
+			l, r := i+1-period, i+1
+			if counts[r]-counts[l] == 0 {
+				dst[i] = math.NaN()
+				continue
+			}
+
+			dst[i] = DType((sums[r] - sums[l]) / float64(period))
+		}
+
+		smas[k] = sma
+	}
+
+	return smas
+}
+
+// EMAMulti returns exponential moving averages of column for every period of periods in a single pass.
+// Results are the same as of EMA.
+func EMAMulti(column series.Data, periods []int, adjust bool) (emas []series.Data) {
+	alphas := make([]DType, len(periods))
+	for k, period := range periods {
+		alphas[k] = 2 / (DType(period) + 1)
+	}
+
+	return ewmMulti(column, alphas, adjust, false)
+}
+
+// RSIMulti returns relative strength indexes of column for every period of periods.
+// Price changes are computed once and all averages of gains and losses are updated in a single pass.
+// Results are the same as of RSI.
+func RSIMulti(column series.Data, periods []int, adjust bool) (rsis []series.Data) {
+	var (
+		up   = column.Clone().Diff(1)
+		down = up.Clone()
+	)
+
+	upValues := up.Values()
+	for i, v := range upValues {
+		if v < 0 {
+			upValues[i] = 0
+		}
+	}
+
+	downValues := down.Values()
+	for i, v := range downValues {
+		if v > 0 {
+			downValues[i] = 0
+		}
+	}
+
+	down = down.Abs()
+
+	alphas := make([]DType, len(periods))
+	for k, period := range periods {
+		alphas[k] = DType(1.0 / float64(period))
+	}
+
+	var (
+		gains  = ewmMulti(up, alphas, adjust, true)
+		losses = ewmMulti(down, alphas, adjust, true)
+	)
+
+	rsis = make([]series.Data, len(periods))
+
+	for k := range periods {
+		rsis[k] = relativeStrength(gains[k], losses[k])
+	}
+
+	return rsis
+}
+
+// ewmMulti computes exponentially weighted means for every alpha of alphas in a single pass over column.
+// It follows series.ExpWindow.Mean exactly.
+func ewmMulti(column series.Data, alphas []DType, adjust, ignoreNA bool) (means []series.Data) {
+	var (
+		values = column.Values()
+		dst    = make([][]DType, len(alphas))
+		last   = make([]DType, len(alphas))
+		weight = make([]DType, len(alphas))
+	)
+
+	means = make([]series.Data, len(alphas))
+
+	for k := range alphas {
+		means[k] = column.Clone()
+		dst[k] = means[k].Values()
+		weight[k] = 1
+	}
+
+	if len(values) == 0 {
+		return means
+	}
+
+	if !adjust {
+		first := values[0]
+		if series.IsNA(first) {
+			first = 0
+		}
+
+		for k := range alphas {
+			last[k] = first
+			dst[k][0] = first
+		}
+	}
+
+	for t, x := range values {
+		na := series.IsNA(x)
+
+		if !adjust && t == 0 {
+			continue
+		}
+
+		for k, alpha := range alphas {
+			if adjust {
+				w := (1-alpha)*weight[k] + 1
+
+				if na {
+					if ignoreNA {
+						weight[k] = w
+					}
+					dst[k][t] = last[k]
+					continue
+				}
+
+				last[k] = last[k] + (x-last[k])/w
+				weight[k] = w
+			} else if !na {
+				last[k] = ((1 - alpha) * last[k]) + (alpha * x)
+			}
+
+			dst[k][t] = last[k]
+		}
+	}
+
+	return means
+}
@@ -1,27 +1,24 @@
 package fta
 
 import (
-	"encoding/csv"
-	"errors"
-	"fmt"
-	"io"
-	"strconv"
+	"sort"
 	"time"
 
 	"github.com/WinPooh32/series"
-)
-
-type UnixTime int
-
-const (
-	Seconds UnixTime = iota
-	Milliseconds
+	"github.com/WinPooh32/series/math"
 )
 
 // OHLCV is a data frame of open, high, close, volume columns.
 // Implements github.com/pplcc/plotext.TOHLCVer interface.
 type OHLCV struct{ Open, High, Low, Close, Volume series.Data }
 
+// Bar is a single time, open, high, low, close, volume tuple.
+// Time is the number of nanoseconds since epoch.
+type Bar struct {
+	Time                           int64
+	Open, High, Low, Close, Volume DType
+}
+
 // Clone returns full copy of ohlcv.
 func (ohlcv OHLCV) Clone() OHLCV {
 	return OHLCV{
@@ -60,6 +57,160 @@ func (ohlcv OHLCV) Resample(interval int64) OHLCV {
 	}
 }
 
+// Column is an extra column of the frame, e.g. trades count, quote volume or open interest.
+type Column struct {
+	Name string
+	Data series.Data
+	// Agg aggregates values of the sample on downsampling,
+	// e.g. series.Sum, series.Last, series.Mean or a custom function.
+	Agg series.AggregateFunc
+}
+
+// ResampleColumns returns resampled copies of ohlcv and extra columns.
+// Ohlcv is resampled like Resample does, extra columns are aggregated by their own functions.
+// Upsampled extra columns are interpolated linearly.
+func (ohlcv OHLCV) ResampleColumns(interval int64, columns []Column) (OHLCV, []Column) {
+	const origin = series.OriginEpoch
+
+	resampled := make([]Column, len(columns))
+
+	for i, column := range columns {
+		data := column.Data.Clone()
+
+		if interval < data.Freq() {
+			data = data.Resample(interval, origin).Interpolate(series.InterpolationLinear)
+		} else {
+			data = data.Resample(interval, origin).Apply(column.Agg)
+		}
+
+		resampled[i] = Column{Name: column.Name, Data: data, Agg: column.Agg}
+	}
+
+	return ohlcv.Resample(interval), resampled
+}
+
+// DedupStrategy is the strategy of resolving bars with duplicate timestamps.
+type DedupStrategy int
+
+const (
+	// KeepFirst keeps the first bar of duplicates.
+	KeepFirst DedupStrategy = iota
+	// KeepLast keeps the last bar of duplicates.
+	KeepLast
+	// Merge merges duplicates into one bar: first open, max high, min low, last close and total volume.
+	Merge
+)
+
+// Sort returns copy of ohlcv sorted by time.
+// Bars with equal timestamps keep their order.
+func (ohlcv OHLCV) Sort() OHLCV {
+	index := ohlcv.Close.Index()
+
+	order := make([]int, len(index))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return index[order[i]] < index[order[j]]
+	})
+
+	return OHLCV{
+		Open:   permute(ohlcv.Open, order),
+		High:   permute(ohlcv.High, order),
+		Low:    permute(ohlcv.Low, order),
+		Close:  permute(ohlcv.Close, order),
+		Volume: permute(ohlcv.Volume, order),
+	}
+}
+
+// Dedup returns copy of ohlcv sorted by time with duplicate timestamps resolved by strategy,
+// so the index becomes strictly increasing.
+func (ohlcv OHLCV) Dedup(strategy DedupStrategy) OHLCV {
+	ohlcv = ohlcv.Sort()
+
+	var (
+		index = ohlcv.Close.Index()
+
+		o = ohlcv.Open.Values()
+		h = ohlcv.High.Values()
+		l = ohlcv.Low.Values()
+		c = ohlcv.Close.Values()
+		v = ohlcv.Volume.Values()
+
+		n int
+	)
+
+	for i := range index {
+		if n > 0 && index[i] == index[n-1] {
+			j := n - 1
+
+			switch strategy {
+			case KeepFirst:
+			case KeepLast:
+				o[j], h[j], l[j], c[j], v[j] = o[i], h[i], l[i], c[i], v[i]
+			case Merge:
+				h[j] = math.Max(h[j], h[i])
+				l[j] = math.Min(l[j], l[i])
+				c[j] = c[i]
+				v[j] += v[i]
+			default:
+				panic("unknown dedup strategy")
+			}
+
+			continue
+		}
+
+		index[n] = index[i]
+		o[n], h[n], l[n], c[n], v[n] = o[i], h[i], l[i], c[i], v[i]
+		n++
+	}
+
+	freq := ohlcv.Close.Freq()
+
+	return OHLCV{
+		Open:   series.MakeData(freq, index[:n], o[:n]),
+		High:   series.MakeData(freq, append([]int64(nil), index[:n]...), h[:n]),
+		Low:    series.MakeData(freq, append([]int64(nil), index[:n]...), l[:n]),
+		Close:  series.MakeData(freq, append([]int64(nil), index[:n]...), c[:n]),
+		Volume: series.MakeData(freq, append([]int64(nil), index[:n]...), v[:n]),
+	}
+}
+
+// HeikinAshi returns the frame of Heikin-Ashi candles. The close is the average of open, high, low and close,
+// the open is the midpoint of the previous Heikin-Ashi candle body and the first one is the midpoint of the first bar body.
+// High and low are extended to the Heikin-Ashi body, volume is copied.
+func (ohlcv OHLCV) HeikinAshi() OHLCV {
+	var (
+		o = ohlcv.Open.Values()
+		h = ohlcv.High.Values()
+		l = ohlcv.Low.Values()
+		c = ohlcv.Close.Values()
+
+		ha = ohlcv.Clone()
+
+		haOpen  = ha.Open.Values()
+		haHigh  = ha.High.Values()
+		haLow   = ha.Low.Values()
+		haClose = ha.Close.Values()
+	)
+
+	for i := range haClose {
+		haClose[i] = (o[i] + h[i] + l[i] + c[i]) / 4
+
+		if i == 0 {
+			haOpen[i] = (o[i] + c[i]) / 2
+		} else {
+			haOpen[i] = (haOpen[i-1] + haClose[i-1]) / 2
+		}
+
+		haHigh[i] = math.Max(h[i], math.Max(haOpen[i], haClose[i]))
+		haLow[i] = math.Min(l[i], math.Min(haOpen[i], haClose[i]))
+	}
+
+	return ha
+}
+
 // Slice slices ohlcv frame.
 func (ohlcv OHLCV) Slice(begin, end int) OHLCV {
 	return OHLCV{
@@ -76,6 +227,19 @@ func (ohlcv OHLCV) Len() int {
 	return ohlcv.Open.Len()
 }
 
+// Bar returns bar at i position.
+// i can be negative.
+func (ohlcv OHLCV) Bar(i int) Bar {
+	return Bar{
+		Time:   ohlcv.Close.IndexAt(i),
+		Open:   ohlcv.Open.At(i),
+		High:   ohlcv.High.At(i),
+		Low:    ohlcv.Low.At(i),
+		Close:  ohlcv.Close.At(i),
+		Volume: ohlcv.Volume.At(i),
+	}
+}
+
 // TOHLCV returns an time, open, high, low, close, volume tuple.
 func (ohlcv OHLCV) TOHLCV(i int) (t float64, o float64, h float64, l float64, c float64, v float64) {
 	t = float64(ohlcv.Open.Index()[i] / int64(time.Second))
@@ -87,90 +251,59 @@ func (ohlcv OHLCV) TOHLCV(i int) (t float64, o float64, h float64, l float64, c
 	return
 }
 
-// ReadCSV parses ohlcv from csv reader.
-// The columns are read at this order: Time Open High Low Close Volume.
-// freq is a sample size, usually it's time.Second or time.Millisecond.
-func ReadCSV(reader *csv.Reader, freq int64, unixTime UnixTime) (ohlcv OHLCV, err error) {
-	const (
-		Time = iota
-		Open
-		High
-		Low
-		Close
-		Volume
-	)
-
+// permute returns copy of column reordered by order of positions.
+func permute(column series.Data, order []int) series.Data {
 	var (
-		T []int64
-		O,
-		H,
-		L,
-		C,
-		V []series.DType
-	)
-
-	for {
-		record, err := reader.Read()
-		if errors.Is(err, io.EOF) {
-			break
-		}
+		index  = make([]int64, len(order))
+		values = make([]DType, len(order))
 
-		if err != nil {
-			return ohlcv, fmt.Errorf("read csv: %w", err)
-		}
-
-		ts, err := strconv.ParseInt(record[Time], 10, 64)
-		if err != nil {
-			return ohlcv, fmt.Errorf("parse int: field 'Time': %w", err)
-		}
-
-		o, err := strconv.ParseFloat(record[Open], 64)
-		if err != nil {
-			return ohlcv, fmt.Errorf("parse float: field 'Open': %w", err)
-		}
-
-		h, err := strconv.ParseFloat(record[High], 64)
-		if err != nil {
-			return ohlcv, fmt.Errorf("parse float: field 'High': %w", err)
-		}
-
-		l, err := strconv.ParseFloat(record[Low], 64)
-		if err != nil {
-			return ohlcv, fmt.Errorf("parse float: field 'Low': %w", err)
-		}
+		srcIndex  = column.Index()
+		srcValues = column.Values()
+	)
 
-		c, err := strconv.ParseFloat(record[Close], 64)
-		if err != nil {
-			return ohlcv, fmt.Errorf("parse float: field 'Close': %w", err)
-		}
+	for i, j := range order {
+		index[i] = srcIndex[j]
+		values[i] = srcValues[j]
+	}
 
-		v, err := strconv.ParseFloat(record[Volume], 64)
-		if err != nil {
-			return ohlcv, fmt.Errorf("parse float: field 'Volume': %w", err)
-		}
+	return series.MakeData(column.Freq(), index, values)
+}
 
-		switch unixTime {
-		case Seconds:
-			ts *= int64(time.Second)
-		case Milliseconds:
-			ts *= int64(time.Millisecond)
-		}
+// ohlcvBuilder accumulates bars in growable columns buffers.
+type ohlcvBuilder struct {
+	t             []int64
+	o, h, l, c, v []DType
+}
 
-		T = append(T, ts)
-		O = append(O, DType(o))
-		H = append(H, DType(h))
-		L = append(L, DType(l))
-		C = append(C, DType(c))
-		V = append(V, DType(v))
+func newOHLCVBuilder(capacity int) *ohlcvBuilder {
+	if capacity < 0 {
+		capacity = 0
 	}
-
-	ohlcv = OHLCV{
-		Open:   series.MakeData(freq, T, O),
-		High:   series.MakeData(freq, T, H),
-		Low:    series.MakeData(freq, T, L),
-		Close:  series.MakeData(freq, T, C),
-		Volume: series.MakeData(freq, T, V),
+	return &ohlcvBuilder{
+		t: make([]int64, 0, capacity),
+		o: make([]DType, 0, capacity),
+		h: make([]DType, 0, capacity),
+		l: make([]DType, 0, capacity),
+		c: make([]DType, 0, capacity),
+		v: make([]DType, 0, capacity),
 	}
+}
+
+func (b *ohlcvBuilder) append(bar Bar) {
+	b.t = append(b.t, bar.Time)
+	b.o = append(b.o, bar.Open)
+	b.h = append(b.h, bar.High)
+	b.l = append(b.l, bar.Low)
+	b.c = append(b.c, bar.Close)
+	b.v = append(b.v, bar.Volume)
+}
 
-	return ohlcv, nil
+func (b *ohlcvBuilder) build(freq int64) OHLCV {
+	return OHLCV{
+		Open:   series.MakeData(freq, b.t, b.o),
+		High:   series.MakeData(freq, b.t, b.h),
+		Low:    series.MakeData(freq, b.t, b.l),
+		Close:  series.MakeData(freq, b.t, b.c),
+		Volume: series.MakeData(freq, b.t, b.v),
+	}
 }
@@ -0,0 +1,175 @@
+package fta
+
+import (
+	"sort"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// OutlierMethod is the method of outliers detection.
+type OutlierMethod int
+
+const (
+	// OutlierZScore marks values which are farther than threshold standard deviations from the mean.
+	OutlierZScore OutlierMethod = iota
+	// OutlierMAD marks values which are farther than threshold scaled median absolute deviations from the median.
+	// MAD is scaled by 1.4826 to be consistent with the standard deviation of normal distribution.
+	OutlierMAD
+	// OutlierIQR marks values below Q1 - threshold*IQR or above Q3 + threshold*IQR (Tukey's fences).
+	OutlierIQR
+)
+
+// DetectOutliers returns mask series where outliers are marked by 1 and other values by 0.
+// NaN values are never marked.
+func DetectOutliers(column series.Data, method OutlierMethod, threshold float64) (mask series.Data) {
+	var (
+		values = column.Values()
+		sorted = sortedNotNA(values)
+		thr    = DType(threshold)
+
+		lower DType
+		upper DType
+	)
+
+	switch method {
+	case OutlierZScore:
+		mean := series.Mean(series.MakeValues(sorted))
+		std := series.Std(series.MakeValues(sorted), mean, 1)
+		lower = mean - thr*std
+		upper = mean + thr*std
+
+	case OutlierMAD:
+		median := quantile(sorted, 0.5)
+
+		deviations := make([]DType, len(sorted))
+		for i, v := range sorted {
+			deviations[i] = math.Abs(v - median)
+		}
+		sort.Sort(series.DTypeSlice(deviations))
+
+		mad := 1.4826 * quantile(deviations, 0.5)
+		lower = median - thr*mad
+		upper = median + thr*mad
+
+	case OutlierIQR:
+		q1 := quantile(sorted, 0.25)
+		q3 := quantile(sorted, 0.75)
+		iqr := q3 - q1
+		lower = q1 - thr*iqr
+		upper = q3 + thr*iqr
+
+	default:
+		panic("unknown outlier detection method")
+	}
+
+	mask = column.Clone()
+
+	m := mask.Values()
+	for i, v := range values {
+		if !series.IsNA(v) && (v < lower || v > upper) {
+			m[i] = 1
+		} else {
+			m[i] = 0
+		}
+	}
+
+	return mask
+}
+
+// Clip limits values to the [lower, upper] range.
+func Clip(column series.Data, lower, upper float64) (clipped series.Data) {
+	clipped = column.Clone().Apply(func(v DType) DType {
+		switch {
+		case v < DType(lower):
+			return DType(lower)
+		case v > DType(upper):
+			return DType(upper)
+		default:
+			return v
+		}
+	})
+	return clipped
+}
+
+// Winsorize limits values to the range between lower and upper quantiles, e.g. 0.01 and 0.99.
+func Winsorize(column series.Data, lower, upper float64) (winsorized series.Data) {
+	sorted := sortedNotNA(column.Values())
+
+	winsorized = Clip(
+		column,
+		float64(quantile(sorted, DType(lower))),
+		float64(quantile(sorted, DType(upper))),
+	)
+
+	return winsorized
+}
+
+// RemoveOutliers returns copy of ohlcv with bad prints replaced.
+// Outliers are detected in deviations of every price column from its rolling median over period bars.
+// If any price of the bar is an outlier, all prices of the bar are linearly interpolated from the neighbour bars,
+// so open, high, low and close stay consistent. Volume is kept untouched.
+// Prices stay NaN when no valid values are left to interpolate from.
+func (ohlcv OHLCV) RemoveOutliers(method OutlierMethod, threshold float64, period int) OHLCV {
+	ohlcv = ohlcv.Clone()
+
+	prices := []series.Data{ohlcv.Open, ohlcv.High, ohlcv.Low, ohlcv.Close}
+
+	bad := make([]bool, ohlcv.Len())
+
+	for _, column := range prices {
+		deviation := column.Clone().Sub(SMM(column, period))
+		mask := DetectOutliers(deviation, method, threshold)
+
+		for i, v := range mask.Values() {
+			if v != 0 {
+				bad[i] = true
+			}
+		}
+	}
+
+	for _, column := range prices {
+		values := column.Values()
+		valid := false
+		for i, b := range bad {
+			if b {
+				values[i] = math.NaN()
+			}
+			valid = valid || !math.IsNaN(values[i])
+		}
+		// Nothing is left to interpolate from, series.Data.Lerp panics on NaN only columns.
+		if valid {
+			column.Lerp().Pad()
+		}
+	}
+
+	return ohlcv
+}
+
+// sortedNotNA returns sorted copy of values without NA values.
+func sortedNotNA(values []DType) []DType {
+	sorted := Dropna(series.MakeValues(values)).Values()
+	sort.Sort(series.DTypeSlice(sorted))
+	return sorted
+}
+
+// quantile returns q-th quantile of sorted values using linear interpolation.
+func quantile(sorted []DType, q DType) DType {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+
+	pos := q * DType(len(sorted)-1)
+	l := int(math.Floor(pos))
+
+	if l < 0 {
+		return sorted[0]
+	}
+	if l >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+
+	frac := pos - DType(l)
+
+	return sorted[l] + frac*(sorted[l+1]-sorted[l])
+}
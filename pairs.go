@@ -0,0 +1,180 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// HedgeRatio returns rolling hedge ratio of y against x.
+// The ratio is the slope b of ordinary least squares regression y = a + b*x over the rolling window.
+func HedgeRatio(y, x series.Data, period int) (hedge series.Data) {
+	hedge, _, _, _ = RollingOLS(y, x, period)
+	return hedge
+}
+
+// Spread returns spread of two price series: y - hedge*x.
+func Spread(y, x, hedge series.Data) (spread series.Data) {
+	spread = y.Clone().Sub(x.Clone().Mul(hedge))
+	return spread
+}
+
+// ADF returns the Augmented Dickey-Fuller test statistic of the series with constant term and lags of differences.
+// The more negative the statistic, the stronger the evidence against the unit root, i.e. the series is mean reverting.
+// Approximate critical values are -3.43 (1%), -2.86 (5%) and -2.57 (10%).
+// NaN values are skipped.
+func ADF(column series.Data, lags int) (stat DType) {
+	y := Dropna(column).Values()
+
+	n := len(y)
+	if n < lags+3 {
+		return math.NaN()
+	}
+
+	dy := make([]float64, n-1)
+	for i := range dy {
+		dy[i] = float64(y[i+1] - y[i])
+	}
+
+	var (
+		x   = make([][]float64, 0, len(dy)-lags)
+		obs = make([]float64, 0, len(dy)-lags)
+	)
+
+	// Regress dy[t] on 1, y[t], dy[t-1], ..., dy[t-lags].
+	for t := lags; t < len(dy); t++ {
+		row := make([]float64, 2+lags)
+		row[0] = 1
+		row[1] = float64(y[t])
+		for l := 1; l <= lags; l++ {
+			row[1+l] = dy[t-l]
+		}
+		x = append(x, row)
+		obs = append(obs, dy[t])
+	}
+
+	res, ok := ols(x, obs)
+	if !ok || res.stderr[1] == 0 {
+		return math.NaN()
+	}
+
+	stat = DType(res.beta[1] / res.stderr[1])
+
+	return stat
+}
+
+// Cointegration performs the Engle-Granger two-step cointegration test of two price series.
+// It regresses y on x over the full sample and applies ADF to the residuals.
+// Returns the test statistic and the hedge ratio of the regression.
+// The statistic must be compared with Engle-Granger critical values
+// which are approximately -3.90 (1%), -3.34 (5%) and -3.04 (10%) for two series.
+func Cointegration(y, x series.Data, lags int) (stat, hedge DType) {
+	var (
+		yv = y.Values()
+		xv = x.Values()
+
+		rows = make([][]float64, 0, len(yv))
+		obs  = make([]float64, 0, len(yv))
+	)
+
+	for i := range yv {
+		if series.IsNA(yv[i]) || series.IsNA(xv[i]) {
+			continue
+		}
+		rows = append(rows, []float64{1, float64(xv[i])})
+		obs = append(obs, float64(yv[i]))
+	}
+
+	res, ok := ols(rows, obs)
+	if !ok {
+		return math.NaN(), math.NaN()
+	}
+
+	var (
+		intercept = DType(res.beta[0])
+		slope     = DType(res.beta[1])
+		residuals = make([]DType, len(obs))
+	)
+
+	for i, row := range rows {
+		residuals[i] = DType(obs[i]) - intercept - slope*DType(row[1])
+	}
+
+	stat = ADF(series.MakeValues(residuals), lags)
+
+	return stat, slope
+}
+
+// HalfLife returns the half-life of mean reversion of the series in bars.
+// The series is modeled as Ornstein-Uhlenbeck process, its speed of reversion is estimated
+// by regression of changes y[t]-y[t-1] on lagged values y[t-1].
+// Returns NaN if the series isn't mean reverting.
+// NaN values are skipped.
+func HalfLife(column series.Data) (halfLife DType) {
+	halfLife = halfLifeOf(Dropna(column).Values())
+	return halfLife
+}
+
+// RollingHalfLife returns the half-life of mean reversion over the rolling window.
+func RollingHalfLife(column series.Data, period int) (halfLife series.Data) {
+	halfLife = column.Clone()
+
+	var (
+		values = halfLife.Values()
+		src    = column.Values()
+	)
+
+	for i := range values {
+		if i < period-1 {
+			values[i] = math.NaN()
+			continue
+		}
+		values[i] = halfLifeOf(src[i-period+1 : i+1])
+	}
+
+	return halfLife
+}
+
+func halfLifeOf(values []DType) DType {
+	if len(values) < 3 {
+		return math.NaN()
+	}
+
+	var (
+		lagged = values[:len(values)-1]
+		delta  = make([]DType, len(lagged))
+	)
+
+	for i := range delta {
+		delta[i] = values[i+1] - values[i]
+	}
+
+	lambda := slope(lagged, delta)
+	if series.IsNA(lambda) || lambda >= 0 {
+		return math.NaN()
+	}
+
+	return -math.Ln2 / lambda
+}
+
+// slope returns slope of ordinary least squares regression y = a + b*x.
+func slope(x, y []DType) DType {
+	n := DType(len(x))
+
+	var sumX, sumY, sumXY, sumXX DType
+	for i := range x {
+		if series.IsNA(x[i]) || series.IsNA(y[i]) {
+			return math.NaN()
+		}
+		sumX += x[i]
+		sumY += y[i]
+		sumXY += x[i] * y[i]
+		sumXX += x[i] * x[i]
+	}
+
+	den := n*sumXX - sumX*sumX
+	if den == 0 {
+		return math.NaN()
+	}
+
+	return (n*sumXY - sumX*sumY) / den
+}
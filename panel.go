@@ -0,0 +1,81 @@
+package fta
+
+import (
+	"sort"
+
+	"github.com/WinPooh32/series"
+)
+
+// Panel is a collection of ohlcv frames of multiple symbols.
+// Frames may have different lengths and timestamps.
+type Panel map[string]OHLCV
+
+// Symbols returns sorted symbols of the panel.
+func (panel Panel) Symbols() []string {
+	symbols := make([]string, 0, len(panel))
+	for symbol := range panel {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// Closes returns close columns of symbols aligned to the timestamps shared by all of them.
+func (panel Panel) Closes(symbols []string) []series.Data {
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	first := panel[symbols[0]].Close
+	index := first.Index()
+
+	for _, symbol := range symbols[1:] {
+		index = intersectIndex(index, panel[symbol].Close.Index())
+	}
+
+	closes := make([]series.Data, len(symbols))
+	for i, symbol := range symbols {
+		closes[i] = reindex(panel[symbol].Close, first.Freq(), index, FillNone)
+	}
+
+	return closes
+}
+
+// Map applies fn to frames of all symbols in order of Symbols and returns results by symbols.
+// Progress receives the number of processed symbols, nil disables reporting.
+func (panel Panel) Map(fn func(symbol string, ohlcv OHLCV) series.Data, progress ProgressFunc) map[string]series.Data {
+	var (
+		symbols = panel.Symbols()
+		results = make(map[string]series.Data, len(symbols))
+		tracker = NewProgressTracker(progress, int64(len(symbols)))
+	)
+
+	for _, symbol := range symbols {
+		results[symbol] = fn(symbol, panel[symbol])
+		tracker.Add(1)
+	}
+
+	tracker.Finish()
+
+	return results
+}
+
+// intersectIndex returns timestamps present in both sorted indices.
+func intersectIndex(a, b []int64) []int64 {
+	index := make([]int64, 0, len(a))
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			index = append(index, a[i])
+			i++
+			j++
+		}
+	}
+
+	return index
+}
@@ -0,0 +1,138 @@
+package fta
+
+import (
+	"time"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// PivotMethod is the formula of pivot levels.
+type PivotMethod int
+
+const (
+	// PivotClassic is the floor traders pivot: P = (H+L+C)/3, R1 = 2P-L, S1 = 2P-H, R2 = P+(H-L), S2 = P-(H-L),
+	// R3 = H+2(P-L), S3 = L-2(H-P).
+	PivotClassic PivotMethod = iota
+	// PivotFibonacci places levels at 0.382, 0.618 and 1 of the previous range from the classic pivot.
+	PivotFibonacci
+	// PivotCamarilla places four levels at 1.1/12, 1.1/6, 1.1/4 and 1.1/2 of the previous range from the previous close.
+	PivotCamarilla
+	// PivotWoodie weights the previous close twice: P = (H+L+2C)/4, levels follow the classic formulas.
+	PivotWoodie
+)
+
+// Pivots are support and resistance levels of every bar computed from high, low and close of the previous session.
+// R4 and S4 are defined only by PivotCamarilla, they are NaN for other methods.
+type Pivots struct {
+	Pivot          series.Data
+	R1, R2, R3, R4 series.Data
+	S1, S2, S3, S4 series.Data
+}
+
+// PIVOT returns pivot levels of daily sessions starting at midnight UTC.
+// Levels are constant within the session and NaN during the first session.
+func PIVOT(ohlcv OHLCV, method PivotMethod) Pivots {
+	return PIVOTSession(ohlcv, method, int64(24*time.Hour), 0)
+}
+
+// PIVOTSession returns pivot levels of sessions of the given length aligned to the unix epoch shifted by offset,
+// see SessionVWAP. The previous session is the last one with bars, so gaps like weekends are skipped.
+func PIVOTSession(ohlcv OHLCV, method PivotMethod, session, offset int64) Pivots {
+	var (
+		h     = ohlcv.High.Values()
+		l     = ohlcv.Low.Values()
+		c     = ohlcv.Close.Values()
+		index = ohlcv.Close.Index()
+
+		levels = make([]series.Data, 9)
+		values = make([][]DType, 9)
+	)
+
+	for k := range levels {
+		levels[k] = ohlcv.Close.Clone()
+		values[k] = levels[k].Values()
+	}
+
+	var (
+		current                   int64
+		high, low, close          = math.NaN(), math.NaN(), math.NaN()
+		prevHigh, prevLow, prevCl = math.NaN(), math.NaN(), math.NaN()
+		row                       [9]DType
+	)
+
+	for i, ts := range index {
+		if start := sessionStart(ts, session, offset); i == 0 || start != current {
+			if i > 0 {
+				prevHigh, prevLow, prevCl = high, low, close
+			}
+			current = start
+			high, low, close = math.NaN(), math.NaN(), math.NaN()
+
+			row = pivotLevels(method, prevHigh, prevLow, prevCl)
+		}
+
+		if series.IsNA(high) || h[i] > high {
+			high = h[i]
+		}
+		if series.IsNA(low) || l[i] < low {
+			low = l[i]
+		}
+		if !series.IsNA(c[i]) {
+			close = c[i]
+		}
+
+		for k := range values {
+			values[k][i] = row[k]
+		}
+	}
+
+	return Pivots{
+		Pivot: levels[0],
+		R1:    levels[1],
+		R2:    levels[2],
+		R3:    levels[3],
+		R4:    levels[4],
+		S1:    levels[5],
+		S2:    levels[6],
+		S3:    levels[7],
+		S4:    levels[8],
+	}
+}
+
+// pivotLevels returns pivot, R1-R4 and S1-S4 by high, low and close of the previous session.
+func pivotLevels(method PivotMethod, h, l, c DType) (row [9]DType) {
+	var (
+		nan = math.NaN()
+		rng = h - l
+		p   = (h + l + c) / 3
+	)
+
+	switch method {
+	case PivotClassic, PivotWoodie:
+		if method == PivotWoodie {
+			p = (h + l + 2*c) / 4
+		}
+		return [9]DType{
+			p,
+			2*p - l, p + rng, h + 2*(p-l), nan,
+			2*p - h, p - rng, l - 2*(h-p), nan,
+		}
+
+	case PivotFibonacci:
+		return [9]DType{
+			p,
+			p + 0.382*rng, p + 0.618*rng, p + rng, nan,
+			p - 0.382*rng, p - 0.618*rng, p - rng, nan,
+		}
+
+	case PivotCamarilla:
+		return [9]DType{
+			p,
+			c + rng*1.1/12, c + rng*1.1/6, c + rng*1.1/4, c + rng*1.1/2,
+			c - rng*1.1/12, c - rng*1.1/6, c - rng*1.1/4, c - rng*1.1/2,
+		}
+	}
+
+	panic("unknown pivot method")
+}
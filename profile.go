@@ -0,0 +1,224 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// ValueAreaFraction is the fraction of total volume of a profile inside its value area.
+const ValueAreaFraction = 0.7
+
+// Profile is the histogram of volume traded at prices of equal buckets between Low and High.
+type Profile struct {
+	// Start is the time of the first bar of the profile.
+	Start int64
+	// Low and High are the price range of the profile.
+	Low, High DType
+	// Volume is the volume of every bucket starting from Low.
+	Volume []DType
+	// POC is the point of control: the middle of the bucket with the highest volume.
+	POC DType
+	// VAH and VAL are high and low of the value area: the buckets around POC holding ValueAreaFraction of volume.
+	VAH, VAL DType
+}
+
+// Bucket returns the low and the high price of i-th bucket.
+func (p Profile) Bucket(i int) (low, high DType) {
+	step := p.step()
+	return p.Low + DType(i)*step, p.Low + DType(i+1)*step
+}
+
+func (p Profile) step() DType {
+	if len(p.Volume) == 0 {
+		return 0
+	}
+	return (p.High - p.Low) / DType(len(p.Volume))
+}
+
+// VolumeProfile returns the volume profile of all bars split into the number of bins.
+// Volume of every bar is spread evenly over its high-low range.
+// Levels of the profile are NaN when there are no valid bars.
+func VolumeProfile(ohlcv OHLCV, bins int) Profile {
+	var start int64
+	if ohlcv.Len() > 0 {
+		start = ohlcv.Close.IndexAt(0)
+	}
+
+	return volumeProfile(ohlcv.High.Values(), ohlcv.Low.Values(), ohlcv.Volume.Values(), start, bins)
+}
+
+// VolumeProfileSession returns volume profiles of sessions of the given length aligned to the unix epoch
+// shifted by offset, see SessionVWAP. Sessions without bars are skipped.
+func VolumeProfileSession(ohlcv OHLCV, bins int, session, offset int64) (profiles []Profile) {
+	var (
+		h     = ohlcv.High.Values()
+		l     = ohlcv.Low.Values()
+		v     = ohlcv.Volume.Values()
+		index = ohlcv.Close.Index()
+		begin int
+	)
+
+	for i := range index {
+		if i == len(index)-1 || sessionStart(index[i+1], session, offset) != sessionStart(index[i], session, offset) {
+			profiles = append(profiles, volumeProfile(h[begin:i+1], l[begin:i+1], v[begin:i+1], index[begin], bins))
+			begin = i + 1
+		}
+	}
+
+	return profiles
+}
+
+// VolumeProfileRolling returns POC, VAH and VAL of volume profiles of the last period bars.
+// The first period-1 values are NaN.
+func VolumeProfileRolling(ohlcv OHLCV, bins, period int) (poc, vah, val series.Data) {
+	var (
+		h     = ohlcv.High.Values()
+		l     = ohlcv.Low.Values()
+		v     = ohlcv.Volume.Values()
+		index = ohlcv.Close.Index()
+	)
+
+	poc = ohlcv.Close.Clone()
+	vah = ohlcv.Close.Clone()
+	val = ohlcv.Close.Clone()
+
+	var (
+		pocValues = poc.Values()
+		vahValues = vah.Values()
+		valValues = val.Values()
+	)
+
+	for i := range pocValues {
+		if i < period-1 {
+			pocValues[i], vahValues[i], valValues[i] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+
+		begin := i - period + 1
+		p := volumeProfile(h[begin:i+1], l[begin:i+1], v[begin:i+1], index[begin], bins)
+
+		pocValues[i], vahValues[i], valValues[i] = p.POC, p.VAH, p.VAL
+	}
+
+	return poc, vah, val
+}
+
+// volumeProfile builds the profile of bars, bars with NaN values are skipped.
+func volumeProfile(h, l, v []DType, start int64, bins int) Profile {
+	p := Profile{
+		Start: start,
+		Low:   math.NaN(),
+		High:  math.NaN(),
+		POC:   math.NaN(),
+		VAH:   math.NaN(),
+		VAL:   math.NaN(),
+	}
+
+	valid := func(i int) bool {
+		return !series.IsNA(h[i]) && !series.IsNA(l[i]) && !series.IsNA(v[i])
+	}
+
+	for i := range h {
+		if !valid(i) {
+			continue
+		}
+		if series.IsNA(p.Low) || l[i] < p.Low {
+			p.Low = l[i]
+		}
+		if series.IsNA(p.High) || h[i] > p.High {
+			p.High = h[i]
+		}
+	}
+
+	if bins <= 0 || series.IsNA(p.Low) {
+		return p
+	}
+
+	p.Volume = make([]DType, bins)
+
+	var (
+		step  = p.step()
+		index = func(price DType) int {
+			if step == 0 {
+				return 0
+			}
+			k := int((price - p.Low) / step)
+			if k >= bins {
+				k = bins - 1
+			}
+			return k
+		}
+	)
+
+	for i := range h {
+		if !valid(i) {
+			continue
+		}
+
+		lo, hi := index(l[i]), index(h[i])
+		if lo == hi || h[i] == l[i] {
+			p.Volume[lo] += v[i]
+			continue
+		}
+
+		for k := lo; k <= hi; k++ {
+			bucketLow, bucketHigh := p.Bucket(k)
+			overlap := math.Min(h[i], bucketHigh) - math.Max(l[i], bucketLow)
+			if overlap > 0 {
+				p.Volume[k] += v[i] * overlap / (h[i] - l[i])
+			}
+		}
+	}
+
+	poc := 0
+	for k, vol := range p.Volume {
+		if vol > p.Volume[poc] {
+			poc = k
+		}
+	}
+
+	lo, hi := valueArea(p.Volume, poc, ValueAreaFraction)
+
+	bucketLow, bucketHigh := p.Bucket(poc)
+	p.POC = (bucketLow + bucketHigh) / 2
+	p.VAL, _ = p.Bucket(lo)
+	_, p.VAH = p.Bucket(hi)
+
+	return p
+}
+
+// valueArea returns the range of buckets around poc holding the fraction of total volume.
+// The range grows by one bucket at a time towards the side with higher volume.
+func valueArea(volume []DType, poc int, fraction DType) (lo, hi int) {
+	var total DType
+	for _, vol := range volume {
+		total += vol
+	}
+
+	var (
+		target = fraction * total
+		acc    = volume[poc]
+	)
+
+	lo, hi = poc, poc
+
+	for acc < target && (lo > 0 || hi < len(volume)-1) {
+		up, down := DType(-1), DType(-1)
+		if hi < len(volume)-1 {
+			up = volume[hi+1]
+		}
+		if lo > 0 {
+			down = volume[lo-1]
+		}
+
+		if up >= down {
+			hi++
+			acc += up
+		} else {
+			lo--
+			acc += down
+		}
+	}
+
+	return lo, hi
+}
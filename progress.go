@@ -0,0 +1,91 @@
+package fta
+
+import (
+	"io"
+	"time"
+)
+
+// Progress is the state of long-running operation.
+type Progress struct {
+	// Done and Total are amounts of work in units of the operation, e.g. bytes or symbols.
+	Done, Total int64
+	// Elapsed is the time passed since the start of the operation.
+	Elapsed time.Duration
+}
+
+// Percent returns the completed part of work in percents.
+func (p Progress) Percent() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return 100 * float64(p.Done) / float64(p.Total)
+}
+
+// ETA returns the estimated time left assuming the constant rate of work. Zero means that the estimate is unknown.
+func (p Progress) ETA() time.Duration {
+	if p.Done <= 0 || p.Total <= 0 || p.Done >= p.Total {
+		return 0
+	}
+	return time.Duration(float64(p.Elapsed) * float64(p.Total-p.Done) / float64(p.Done))
+}
+
+// ProgressFunc receives progress of long-running operation.
+// It's called synchronously, so it must return quickly.
+type ProgressFunc func(p Progress)
+
+// progressInterval is the minimal interval between progress reports, the final report is never skipped.
+const progressInterval = 100 * time.Millisecond
+
+// ProgressTracker counts done work and reports it to ProgressFunc at most once per 100ms.
+// Nil tracker and tracker of nil func do nothing. It's not safe for concurrent use.
+type ProgressTracker struct {
+	fn    ProgressFunc
+	total int64
+	done  int64
+	start time.Time
+	last  time.Time
+}
+
+// NewProgressTracker returns tracker of work of total size, nil fn disables reporting.
+func NewProgressTracker(fn ProgressFunc, total int64) *ProgressTracker {
+	now := time.Now()
+	return &ProgressTracker{fn: fn, total: total, start: now, last: now}
+}
+
+// Add adds n units of done work, the progress is reported when the interval since the last report has passed.
+func (t *ProgressTracker) Add(n int64) {
+	if t == nil || t.fn == nil {
+		return
+	}
+
+	t.done += n
+
+	if now := time.Now(); now.Sub(t.last) >= progressInterval {
+		t.last = now
+		t.report(now)
+	}
+}
+
+// Finish reports the final progress.
+func (t *ProgressTracker) Finish() {
+	if t == nil || t.fn == nil {
+		return
+	}
+	t.report(time.Now())
+}
+
+func (t *ProgressTracker) report(now time.Time) {
+	t.fn(Progress{Done: t.done, Total: t.total, Elapsed: now.Sub(t.start)})
+}
+
+// progressReader reports the number of bytes read.
+type progressReader struct {
+	r       io.Reader
+	tracker *ProgressTracker
+}
+
+func (pr progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	pr.tracker.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,190 @@
+package fta
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// DefaultSpikeThreshold is the number of scaled MADs of close log returns beyond which the bar is a price spike.
+const DefaultSpikeThreshold = 5
+
+// Quality is the data-quality report of ohlcv. It counts issues instead of failing on them,
+// so pipelines can decide by thresholds whether the data is good enough.
+type Quality struct {
+	// Bars is the number of bars.
+	Bars int
+	// ExpectedBars is the number of bars between the first and the last bar at the frame frequency.
+	ExpectedBars int
+	// MissingBars is the number of expected timestamps without a bar.
+	// Markets which aren't traded around the clock always miss bars of closed sessions.
+	MissingBars int
+	// MissingValues is the number of bars with NaN in any column.
+	MissingValues int
+	// ZeroVolume is the number of bars with zero volume.
+	ZeroVolume int
+	// Spikes is the number of bars which close log returns are farther than SpikeThreshold scaled MADs from the median.
+	Spikes int
+	// SpikeThreshold is the threshold of spikes detection.
+	SpikeThreshold float64
+	// DuplicateTimestamps is the number of bars with the timestamp of the previous bar.
+	DuplicateTimestamps int
+	// StaleCloses is the number of bars with the same close as the previous bar.
+	StaleCloses int
+}
+
+// MissingPercent returns the percent of missing bars of expected bars.
+func (q Quality) MissingPercent() float64 {
+	return percentOf(q.MissingBars, q.ExpectedBars)
+}
+
+// MissingValuesPercent returns the percent of bars with NaN values.
+func (q Quality) MissingValuesPercent() float64 {
+	return percentOf(q.MissingValues, q.Bars)
+}
+
+// ZeroVolumePercent returns the percent of bars with zero volume.
+func (q Quality) ZeroVolumePercent() float64 {
+	return percentOf(q.ZeroVolume, q.Bars)
+}
+
+// SpikesPercent returns the percent of bars with price spikes.
+func (q Quality) SpikesPercent() float64 {
+	return percentOf(q.Spikes, q.Bars)
+}
+
+// DuplicatePercent returns the percent of bars with duplicate timestamps.
+func (q Quality) DuplicatePercent() float64 {
+	return percentOf(q.DuplicateTimestamps, q.Bars)
+}
+
+// StalePercent returns the percent of bars with stale closes.
+func (q Quality) StalePercent() float64 {
+	return percentOf(q.StaleCloses, q.Bars)
+}
+
+// Score returns the overall quality from 0 to 100: 100 minus percents of all issues.
+func (q Quality) Score() float64 {
+	score := 100 - q.MissingPercent() - q.MissingValuesPercent() - q.ZeroVolumePercent() -
+		q.SpikesPercent() - q.DuplicatePercent() - q.StalePercent()
+	if score < 0 {
+		return 0
+	}
+	return score
+}
+
+// String formats the report as a table.
+func (q Quality) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "bars: %d (expected %d)\n", q.Bars, q.ExpectedBars)
+
+	rows := []struct {
+		name    string
+		count   int
+		percent float64
+	}{
+		{"missing bars", q.MissingBars, q.MissingPercent()},
+		{"missing values", q.MissingValues, q.MissingValuesPercent()},
+		{"zero volume", q.ZeroVolume, q.ZeroVolumePercent()},
+		{fmt.Sprintf("spikes (%g MAD)", q.SpikeThreshold), q.Spikes, q.SpikesPercent()},
+		{"duplicate timestamps", q.DuplicateTimestamps, q.DuplicatePercent()},
+		{"stale closes", q.StaleCloses, q.StalePercent()},
+	}
+
+	for _, row := range rows {
+		fmt.Fprintf(&sb, "%-22s %8d %7.2f%%\n", row.name, row.count, row.percent)
+	}
+
+	fmt.Fprintf(&sb, "score: %.2f\n", q.Score())
+
+	return sb.String()
+}
+
+// QualityReport scores quality of ohlcv data. Bars must be sorted by time.
+// Spikes are detected by OutlierMAD method on close log returns with spikeThreshold,
+// non-positive threshold means DefaultSpikeThreshold.
+// The frequency of the frame is used to count missing bars, when it's zero the frequency is detected from timestamps.
+func QualityReport(ohlcv OHLCV, spikeThreshold float64) (q Quality) {
+	if spikeThreshold <= 0 {
+		spikeThreshold = DefaultSpikeThreshold
+	}
+
+	var (
+		index = ohlcv.Close.Index()
+
+		o = ohlcv.Open.Values()
+		h = ohlcv.High.Values()
+		l = ohlcv.Low.Values()
+		c = ohlcv.Close.Values()
+		v = ohlcv.Volume.Values()
+
+		unique int
+	)
+
+	q.Bars = len(index)
+	q.SpikeThreshold = spikeThreshold
+
+	for i := range index {
+		if series.IsNA(o[i]) || series.IsNA(h[i]) || series.IsNA(l[i]) || series.IsNA(c[i]) || series.IsNA(v[i]) {
+			q.MissingValues++
+		}
+
+		if v[i] == 0 {
+			q.ZeroVolume++
+		}
+
+		if i == 0 {
+			unique++
+			continue
+		}
+
+		if index[i] == index[i-1] {
+			q.DuplicateTimestamps++
+		} else {
+			unique++
+		}
+
+		if c[i] == c[i-1] {
+			q.StaleCloses++
+		}
+	}
+
+	if len(index) > 0 {
+		freq := ohlcv.Close.Freq()
+		if freq <= 0 {
+			freq = detectFreq(index)
+		}
+
+		q.ExpectedBars = unique
+		if freq > 0 {
+			q.ExpectedBars = int((index[len(index)-1]-index[0])/freq) + 1
+		}
+
+		if q.MissingBars = q.ExpectedBars - unique; q.MissingBars < 0 {
+			q.MissingBars = 0
+		}
+	}
+
+	if len(c) > 1 {
+		returns := make([]DType, len(c))
+		returns[0] = math.NaN()
+		for i := 1; i < len(c); i++ {
+			returns[i] = math.Log(c[i] / c[i-1])
+		}
+
+		mask := DetectOutliers(series.MakeValues(returns), OutlierMAD, spikeThreshold)
+		q.Spikes = int(series.Sum(mask))
+	}
+
+	return q
+}
+
+func percentOf(n, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
@@ -0,0 +1,136 @@
+package reference
+
+import (
+	"fmt"
+	stdmath "math"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+)
+
+// Mismatch is the first difference between outputs of optimized and reference implementations.
+type Mismatch struct {
+	// Indicator is the name of indicator and its output.
+	Indicator string
+	// Index is the position of the differing value.
+	Index     int
+	Got, Want fta.DType
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s: at %d got %v, want %v", m.Indicator, m.Index, m.Got, m.Want)
+}
+
+// Compare returns position of the first value of got which differs from want by more than tolerance
+// relative to the magnitude of want, or absolutely for magnitudes less than 1.
+// NaN values are equal to each other. Returns -1 when series are equal.
+func Compare(got, want series.Data, tolerance fta.DType) int {
+	var (
+		g = got.Values()
+		w = want.Values()
+	)
+
+	if len(g) != len(w) {
+		if len(g) < len(w) {
+			return len(g)
+		}
+		return len(w)
+	}
+
+	for i := range g {
+		if !equal(g[i], w[i], tolerance) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Check computes indicators of fta package and their reference implementations on ohlcv
+// and returns mismatches. Periods are chosen to be the usual defaults.
+// Ohlcv must not contain NaN values.
+func Check(ohlcv fta.OHLCV, tolerance fta.DType) (mismatches []Mismatch) {
+	const period = 14
+
+	var (
+		h = ohlcv.High
+		l = ohlcv.Low
+		c = ohlcv.Close
+	)
+
+	type output struct {
+		name      string
+		got, want series.Data
+	}
+
+	var outputs []output
+
+	add := func(name string, got, want series.Data) {
+		outputs = append(outputs, output{name, got, want})
+	}
+
+	add("SMA", fta.SMA(c, period), SMA(c, period))
+	add("SMM", fta.SMM(c, period), SMM(c, period))
+	add("WMA", fta.WMA(c, period), WMA(c, period))
+	add("EMA", fta.EMA(c, period, false), EMA(c, period))
+	add("SSMA", fta.SSMA(c, period, false), SSMA(c, period))
+	add("ROC", fta.ROC(c, period), ROC(c, period))
+
+	macd, signal := fta.MACD(c, 12, 26, 9, false)
+	refMACD, refSignal := MACD(c, 12, 26, 9)
+	add("MACD", macd, refMACD)
+	add("MACD signal", signal, refSignal)
+
+	upper, lower := fta.BBANDS(c, fta.SMA(c, 20), 20, 2)
+	refUpper, refLower := BBANDS(c, 20, 2)
+	add("BBANDS upper", upper, refUpper)
+	add("BBANDS lower", lower, refLower)
+
+	add("PercentB", fta.PercentB(c, fta.SMA(c, 20), 20, 2), PercentB(c, 20, 2))
+	add("RSI", fta.RSI(c, period, false), RSI(c, period))
+	add("RSIWilder", fta.RSIWilder(c, period), RSIWilder(c, period))
+	add("STOCH", fta.STOCH(h, l, c, period), STOCH(h, l, c, period))
+	add("WILLIAMS", fta.WILLIAMS(h, l, c, period), WILLIAMS(h, l, c, period))
+	add("ADL", fta.ADL(h, l, c), ADL(h, l, c))
+
+	dcUpper, dcLower, dcMiddle := fta.DONCHIAN(h, l, period)
+	refDCUpper, refDCLower, refDCMiddle := DONCHIAN(h, l, period)
+	add("DONCHIAN upper", dcUpper, refDCUpper)
+	add("DONCHIAN lower", dcLower, refDCLower)
+	add("DONCHIAN middle", dcMiddle, refDCMiddle)
+
+	add("ApEn", fta.ApEn(c, 50, 2, 0.2), ApEn(c, 50, 2, 0.2))
+	add("SampEn", fta.SampEn(c, 50, 2, 0.2), SampEn(c, 50, 2, 0.2))
+
+	for _, out := range outputs {
+		if i := Compare(out.got, out.want, tolerance); i >= 0 {
+			m := Mismatch{Indicator: out.name, Index: i, Got: nan(), Want: nan()}
+			if i < out.got.Len() {
+				m.Got = out.got.Values()[i]
+			}
+			if i < out.want.Len() {
+				m.Want = out.want.Values()[i]
+			}
+			mismatches = append(mismatches, m)
+		}
+	}
+
+	return mismatches
+}
+
+func equal(a, b, tolerance fta.DType) bool {
+	aNaN := stdmath.IsNaN(float64(a))
+	bNaN := stdmath.IsNaN(float64(b))
+
+	if aNaN || bNaN {
+		return aNaN && bNaN
+	}
+
+	if a == b {
+		return true
+	}
+
+	scale := fta.DType(stdmath.Max(1, stdmath.Abs(float64(b))))
+
+	return fta.DType(stdmath.Abs(float64(a-b))) <= tolerance*scale
+}
@@ -0,0 +1,64 @@
+package reference
+
+import (
+	stdmath "math"
+	"testing"
+
+	"github.com/WinPooh32/fta"
+)
+
+func TestCheck(t *testing.T) {
+	models := []fta.Model{fta.GBM, fta.Heston, fta.RegimeSwitching}
+
+	for _, model := range models {
+		for seed := int64(1); seed <= 5; seed++ {
+			ohlcv := fta.Generate(model, fta.GenerateParams{}, 300, seed)
+
+			for _, m := range Check(ohlcv, 1e-6) {
+				t.Errorf("model %d seed %d: %s", model, seed, m)
+			}
+		}
+	}
+}
+
+func TestEntropyGaps(t *testing.T) {
+	// Windows with NaN values are NaN, rows of templates are rebuilt after them.
+	close := fta.Generate(fta.GBM, fta.GenerateParams{}, 300, 1).Close
+
+	values := close.Values()
+	gap := []int{100, 101, 180}
+	for _, i := range gap {
+		values[i] = nan()
+	}
+
+	const period = 30
+
+	touches := func(i int) bool {
+		for _, g := range gap {
+			if g <= i && i < g+period {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, tt := range []struct {
+		name      string
+		got, want []fta.DType
+	}{
+		{"ApEn", fta.ApEn(close, period, 2, 0.2).Values(), ApEn(close, period, 2, 0.2).Values()},
+		{"SampEn", fta.SampEn(close, period, 2, 0.2).Values(), SampEn(close, period, 2, 0.2).Values()},
+	} {
+		for i, g := range tt.got {
+			if touches(i) {
+				if !stdmath.IsNaN(float64(g)) {
+					t.Errorf("%s: at %d got %v over NaN values", tt.name, i, g)
+				}
+				continue
+			}
+			if !equal(g, tt.want[i], 1e-6) {
+				t.Errorf("%s: at %d got %v, want %v", tt.name, i, g, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,405 @@
+// Package reference provides slow but straightforward implementations of indicators of fta package.
+// They follow textbook definitions with plain loops over windows, so they are easy to verify by reading.
+// Check compares optimized indicators against them.
+//
+// Input data must not contain NaN values. Exponential averages are implemented in not adjusted form only.
+package reference
+
+import (
+	stdmath "math"
+	"sort"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+)
+
+// SMA is the simple moving average.
+func SMA(column series.Data, period int) series.Data {
+	return window(column, period, mean)
+}
+
+// SMM is the simple moving median.
+func SMM(column series.Data, period int) series.Data {
+	return window(column, period, func(w []fta.DType) fta.DType {
+		sorted := append([]fta.DType(nil), w...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		n := len(sorted)
+		if n%2 == 1 {
+			return sorted[n/2]
+		}
+		return (sorted[n/2-1] + sorted[n/2]) / 2
+	})
+}
+
+// WMA is the linearly weighted moving average, the latest value has the largest weight.
+func WMA(column series.Data, period int) series.Data {
+	return window(column, period, func(w []fta.DType) fta.DType {
+		var sum, weights fta.DType
+		for i, v := range w {
+			sum += fta.DType(i+1) * v
+			weights += fta.DType(i + 1)
+		}
+		return sum / weights
+	})
+}
+
+// EMA is the not adjusted exponential moving average with alpha = 2/(period+1).
+func EMA(column series.Data, period int) series.Data {
+	return ewm(column.Values(), 2/(fta.DType(period)+1), column)
+}
+
+// SSMA is the not adjusted smoothed moving average with alpha = 1/period.
+func SSMA(column series.Data, period int) series.Data {
+	return ewm(column.Values(), 1/fta.DType(period), column)
+}
+
+// ROC is the rate of change in percents.
+func ROC(column series.Data, period int) series.Data {
+	values := column.Values()
+
+	return build(column, func(i int) fta.DType {
+		if i < period {
+			return nan()
+		}
+		return divide(values[i]-values[i-period], values[i-period]) * 100
+	})
+}
+
+// MACD is the difference of fast and slow not adjusted EMAs and its signal line.
+func MACD(column series.Data, periodFast, periodSlow, signal int) (macd, macdSignal series.Data) {
+	var (
+		fast = EMA(column, periodFast).Values()
+		slow = EMA(column, periodSlow).Values()
+	)
+
+	macd = build(column, func(i int) fta.DType {
+		return fast[i] - slow[i]
+	})
+
+	macdSignal = EMA(macd, signal)
+
+	return macd, macdSignal
+}
+
+// BBANDS are Bollinger bands around the simple moving average at stdMultiplier sample standard deviations.
+func BBANDS(column series.Data, period int, stdMultiplier float64) (upper, lower series.Data) {
+	var (
+		ma  = SMA(column, period).Values()
+		std = window(column, period, stdDev).Values()
+		k   = fta.DType(stdMultiplier)
+	)
+
+	upper = build(column, func(i int) fta.DType { return ma[i] + k*std[i] })
+	lower = build(column, func(i int) fta.DType { return ma[i] - k*std[i] })
+
+	return upper, lower
+}
+
+// PercentB is the position of the value relative to Bollinger bands: 0 at the lower band and 1 at the upper band.
+func PercentB(column series.Data, period int, stdMultiplier float64) series.Data {
+	var (
+		values       = column.Values()
+		upper, lower = BBANDS(column, period, stdMultiplier)
+		u, l         = upper.Values(), lower.Values()
+	)
+
+	return build(column, func(i int) fta.DType {
+		return divide(values[i]-l[i], u[i]-l[i])
+	})
+}
+
+// RSI is the relative strength index with not adjusted Wilder's smoothing of gains and losses.
+// The first change is undefined, so the averages start from zero. RSI is 100 without losses.
+func RSI(column series.Data, period int) series.Data {
+	var (
+		values = column.Values()
+		alpha  = 1 / fta.DType(period)
+
+		gain, loss fta.DType
+	)
+
+	return build(column, func(i int) fta.DType {
+		if i > 0 {
+			change := values[i] - values[i-1]
+			gain = (1-alpha)*gain + alpha*maxOf(change, 0)
+			loss = (1-alpha)*loss + alpha*maxOf(-change, 0)
+		}
+
+		return 100 * divide(gain, gain+loss)
+	})
+}
+
+// RSIWilder is the relative strength index as defined in Wilder's book.
+func RSIWilder(column series.Data, period int) series.Data {
+	var (
+		values = column.Values()
+		p      = fta.DType(period)
+
+		gain, loss fta.DType
+	)
+
+	return build(column, func(i int) fta.DType {
+		if i < period {
+			return nan()
+		}
+
+		if i == period {
+			for j := 1; j <= period; j++ {
+				change := values[j] - values[j-1]
+				gain += maxOf(change, 0) / p
+				loss += maxOf(-change, 0) / p
+			}
+		} else {
+			change := values[i] - values[i-1]
+			gain = (gain*(p-1) + maxOf(change, 0)) / p
+			loss = (loss*(p-1) + maxOf(-change, 0)) / p
+		}
+
+		return 100 * divide(gain, gain+loss)
+	})
+}
+
+// STOCH is the stochastic oscillator %K in range 0-1.
+func STOCH(high, low, close series.Data, period int) series.Data {
+	var (
+		h = high.Values()
+		l = low.Values()
+		c = close.Values()
+	)
+
+	return build(close, func(i int) fta.DType {
+		if i < period-1 {
+			return nan()
+		}
+
+		highest, lowest := h[i], l[i]
+		for j := i - period + 1; j <= i; j++ {
+			highest = maxOf(highest, h[j])
+			lowest = minOf(lowest, l[j])
+		}
+
+		return divide(c[i]-lowest, highest-lowest)
+	})
+}
+
+// DONCHIAN is the Donchian channel of the highest high and the lowest low over the period.
+func DONCHIAN(high, low series.Data, period int) (upper, lower, middle series.Data) {
+	upper = window(high, period, func(w []fta.DType) fta.DType {
+		highest := w[0]
+		for _, v := range w {
+			highest = maxOf(highest, v)
+		}
+		return highest
+	})
+
+	lower = window(low, period, func(w []fta.DType) fta.DType {
+		lowest := w[0]
+		for _, v := range w {
+			lowest = minOf(lowest, v)
+		}
+		return lowest
+	})
+
+	u, lo := upper.Values(), lower.Values()
+
+	middle = build(high, func(i int) fta.DType {
+		return (u[i] + lo[i]) / 2
+	})
+
+	return upper, lower, middle
+}
+
+// WILLIAMS is Williams %R in range -100..0.
+func WILLIAMS(high, low, close series.Data, period int) series.Data {
+	var (
+		h = high.Values()
+		l = low.Values()
+		c = close.Values()
+	)
+
+	return build(close, func(i int) fta.DType {
+		if i < period-1 {
+			return nan()
+		}
+
+		highest, lowest := h[i], l[i]
+		for j := i - period + 1; j <= i; j++ {
+			highest = maxOf(highest, h[j])
+			lowest = minOf(lowest, l[j])
+		}
+
+		return -100 * divide(highest-c[i], highest-lowest)
+	})
+}
+
+// ADL is the accumulation/distribution line.
+func ADL(high, low, close series.Data) series.Data {
+	var (
+		h = high.Values()
+		l = low.Values()
+		c = close.Values()
+
+		sum fta.DType
+	)
+
+	return build(close, func(i int) fta.DType {
+		sum += divide((c[i]-l[i])-(h[i]-c[i]), h[i]-l[i])
+		return sum
+	})
+}
+
+// window applies fn to every full window of the column, values before the first full window are NaN.
+// ApEn is the rolling approximate entropy with templates of length m matching within r standard deviations.
+func ApEn(column series.Data, period, m int, r float64) series.Data {
+	return window(column, period, func(w []fta.DType) fta.DType {
+		if len(w) <= m+1 {
+			return nan()
+		}
+
+		tolerance := fta.DType(r) * stdDev(w)
+
+		phi := func(m int) fta.DType {
+			n := len(w) - m + 1
+
+			var sum fta.DType
+			for i := 0; i < n; i++ {
+				count := 0
+				for j := 0; j < n; j++ {
+					if chebyshev(w, i, j, m) <= tolerance {
+						count++
+					}
+				}
+				sum += fta.DType(stdmath.Log(float64(count) / float64(n)))
+			}
+
+			return sum / fta.DType(n)
+		}
+
+		return phi(m) - phi(m+1)
+	})
+}
+
+// SampEn is the rolling sample entropy with templates of length m matching within r standard deviations.
+func SampEn(column series.Data, period, m int, r float64) series.Data {
+	return window(column, period, func(w []fta.DType) fta.DType {
+		if len(w) <= m+1 {
+			return nan()
+		}
+
+		var (
+			tolerance = fta.DType(r) * stdDev(w)
+			n         = len(w) - m
+			a, b      int
+		)
+
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if chebyshev(w, i, j, m) <= tolerance {
+					b++
+				}
+				if chebyshev(w, i, j, m+1) <= tolerance {
+					a++
+				}
+			}
+		}
+
+		if a == 0 || b == 0 {
+			return nan()
+		}
+
+		return fta.DType(stdmath.Log(float64(b) / float64(a)))
+	})
+}
+
+func window(column series.Data, period int, fn func(w []fta.DType) fta.DType) series.Data {
+	values := column.Values()
+
+	return build(column, func(i int) fta.DType {
+		if i < period-1 {
+			return nan()
+		}
+		return fn(values[i-period+1 : i+1])
+	})
+}
+
+func ewm(values []fta.DType, alpha fta.DType, like series.Data) series.Data {
+	var last fta.DType
+
+	return build(like, func(i int) fta.DType {
+		if i == 0 {
+			last = values[0]
+		} else {
+			last = (1-alpha)*last + alpha*values[i]
+		}
+		return last
+	})
+}
+
+// build makes series with index of like and values computed by fn in order of the index.
+func build(like series.Data, fn func(i int) fta.DType) series.Data {
+	values := make([]fta.DType, like.Len())
+	for i := range values {
+		values[i] = fn(i)
+	}
+	return series.MakeData(like.Freq(), append([]int64(nil), like.Index()...), values)
+}
+
+func mean(w []fta.DType) fta.DType {
+	var sum fta.DType
+	for _, v := range w {
+		sum += v
+	}
+	return sum / fta.DType(len(w))
+}
+
+// divide follows the default fta.DivNaN policy of division by zero.
+func divide(x, y fta.DType) fta.DType {
+	if y == 0 {
+		return nan()
+	}
+	return x / y
+}
+
+// stdDev is the sample standard deviation.
+func stdDev(w []fta.DType) fta.DType {
+	m := mean(w)
+
+	var ss fta.DType
+	for _, v := range w {
+		ss += (v - m) * (v - m)
+	}
+
+	return sqrt(ss / fta.DType(len(w)-1))
+}
+
+// chebyshev is the largest absolute difference of templates of length m starting at i and j.
+func chebyshev(w []fta.DType, i, j, m int) fta.DType {
+	var d fta.DType
+	for k := 0; k < m; k++ {
+		d = maxOf(d, fta.DType(stdmath.Abs(float64(w[i+k]-w[j+k]))))
+	}
+	return d
+}
+
+func nan() fta.DType {
+	return fta.DType(stdmath.NaN())
+}
+
+func sqrt(x fta.DType) fta.DType {
+	return fta.DType(stdmath.Sqrt(float64(x)))
+}
+
+func maxOf(a, b fta.DType) fta.DType {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minOf(a, b fta.DType) fta.DType {
+	if a < b {
+		return a
+	}
+	return b
+}
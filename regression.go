@@ -0,0 +1,331 @@
+package fta
+
+import (
+	stdmath "math"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// RollingOLS fits ordinary least squares regression y = intercept + slope*x over the rolling window.
+// Residual is the difference between y and its fitted value by the current window.
+// R² is the coefficient of determination of the fit.
+// Windows containing NaN values produce NaNs.
+//
+// Window moments are updated incrementally, so the cost doesn't depend on the period.
+func RollingOLS(y, x series.Data, period int) (slope, intercept, residual, r2 series.Data) {
+	slope = y.Clone()
+	intercept = y.Clone()
+	residual = y.Clone()
+	r2 = y.Clone()
+
+	var (
+		yv = y.Values()
+		xv = x.Values()
+
+		slopeValues     = slope.Values()
+		interceptValues = intercept.Values()
+		residualValues  = residual.Values()
+		r2Values        = r2.Values()
+
+		m   olsMoments
+		nan int
+	)
+
+	for i := range yv {
+		if series.IsNA(xv[i]) || series.IsNA(yv[i]) {
+			nan++
+		} else {
+			m.add(float64(xv[i]), float64(yv[i]))
+		}
+
+		if l := i - period; l >= 0 {
+			if series.IsNA(xv[l]) || series.IsNA(yv[l]) {
+				nan--
+			} else {
+				m.remove(float64(xv[l]), float64(yv[l]))
+			}
+		}
+
+		if i < period-1 || nan > 0 || m.cxx == 0 {
+			slopeValues[i] = math.NaN()
+			interceptValues[i] = math.NaN()
+			residualValues[i] = math.NaN()
+			r2Values[i] = math.NaN()
+			continue
+		}
+
+		b := m.cxy / m.cxx
+		a := m.my - b*m.mx
+
+		slopeValues[i] = DType(b)
+		interceptValues[i] = DType(a)
+		residualValues[i] = yv[i] - DType(a+b*float64(xv[i]))
+
+		if m.cyy == 0 {
+			r2Values[i] = 1
+		} else {
+			r2Values[i] = DType(m.cxy * m.cxy / (m.cxx * m.cyy))
+		}
+	}
+
+	return slope, intercept, residual, r2
+}
+
+// LINREG fits least squares lines to closes of the rolling window against positions of bars.
+// LSMA is the value of the line at the last bar, the least squares moving average.
+// Slope is the change of the line per bar, intercept is its value at the first bar of the window.
+// R² is the coefficient of determination of the fit. Windows containing NaN values produce NaNs.
+//
+// Window moments are updated incrementally, so the cost doesn't depend on the period.
+func LINREG(close series.Data, period int) (lsma, slope, intercept, r2 series.Data) {
+	lsma, slope, intercept, r2, _ = linearRegression(close, period)
+	return lsma, slope, intercept, r2
+}
+
+// LinRegChannel is the linear regression channel: LSMA plus and minus multiplier standard errors of the estimate
+// of the line fitted to the rolling window.
+func LinRegChannel(close series.Data, period int, multiplier float64) (upper, lower series.Data) {
+	lsma, _, _, _, stderr := linearRegression(close, period)
+
+	stderr.MulScalar(DType(multiplier))
+
+	upper = lsma.Clone().Add(stderr)
+	lower = lsma.Sub(stderr)
+	return upper, lower
+}
+
+func linearRegression(close series.Data, period int) (lsma, slope, intercept, r2, stderr series.Data) {
+	lsma = close.Clone()
+	slope = close.Clone()
+	intercept = close.Clone()
+	r2 = close.Clone()
+	stderr = close.Clone()
+
+	var (
+		yv = close.Values()
+
+		lsmaValues      = lsma.Values()
+		slopeValues     = slope.Values()
+		interceptValues = intercept.Values()
+		r2Values        = r2.Values()
+		stderrValues    = stderr.Values()
+
+		m   olsMoments
+		nan int
+	)
+
+	for i := range yv {
+		if series.IsNA(yv[i]) {
+			nan++
+		} else {
+			m.add(float64(i), float64(yv[i]))
+		}
+
+		if l := i - period; l >= 0 {
+			if series.IsNA(yv[l]) {
+				nan--
+			} else {
+				m.remove(float64(l), float64(yv[l]))
+			}
+		}
+
+		if i < period-1 || nan > 0 || m.cxx == 0 {
+			lsmaValues[i] = math.NaN()
+			slopeValues[i] = math.NaN()
+			interceptValues[i] = math.NaN()
+			r2Values[i] = math.NaN()
+			stderrValues[i] = math.NaN()
+			continue
+		}
+
+		b := m.cxy / m.cxx
+		a := m.my - b*m.mx
+
+		lsmaValues[i] = DType(a + b*float64(i))
+		slopeValues[i] = DType(b)
+		interceptValues[i] = DType(a + b*float64(i-period+1))
+
+		sse := stdmath.Max(m.cyy-m.cxy*m.cxy/m.cxx, 0)
+
+		if m.cyy == 0 {
+			r2Values[i] = 1
+		} else {
+			r2Values[i] = DType(1 - sse/m.cyy)
+		}
+
+		if period > 2 {
+			stderrValues[i] = DType(stdmath.Sqrt(sse / float64(period-2)))
+		} else {
+			stderrValues[i] = 0
+		}
+	}
+
+	return lsma, slope, intercept, r2, stderr
+}
+
+// olsMoments are running means and co-moments of x and y.
+type olsMoments struct {
+	n             int
+	mx, my        float64
+	cxx, cyy, cxy float64
+}
+
+func (m *olsMoments) add(x, y float64) {
+	m.n++
+
+	dx := x - m.mx
+	dy := y - m.my
+
+	m.mx += dx / float64(m.n)
+	m.my += dy / float64(m.n)
+
+	m.cxx += dx * (x - m.mx)
+	m.cyy += dy * (y - m.my)
+	m.cxy += dx * (y - m.my)
+}
+
+func (m *olsMoments) remove(x, y float64) {
+	if m.n <= 1 {
+		*m = olsMoments{}
+		return
+	}
+
+	m.n--
+
+	dx := x - m.mx
+	dy := y - m.my
+
+	m.mx -= dx / float64(m.n)
+	m.my -= dy / float64(m.n)
+
+	m.cxx -= dx * (x - m.mx)
+	m.cyy -= dy * (y - m.my)
+	m.cxy -= dx * (y - m.my)
+}
+
+// olsResult is the result of ordinary least squares fit.
+type olsResult struct {
+	beta   []float64 // Coefficients.
+	stderr []float64 // Standard errors of coefficients.
+	rss    float64   // Residual sum of squares.
+}
+
+// ols fits y = X*beta by ordinary least squares.
+// Rows of x are observations, columns are regressors.
+// Returns false if the problem is underdetermined or singular.
+func ols(x [][]float64, y []float64) (res olsResult, ok bool) {
+	n := len(y)
+	if n == 0 {
+		return res, false
+	}
+
+	k := len(x[0])
+	if n <= k {
+		return res, false
+	}
+
+	// Normal equations: (X'X) beta = X'y.
+	xtx := make([][]float64, k)
+	for i := range xtx {
+		xtx[i] = make([]float64, k)
+	}
+	xty := make([]float64, k)
+
+	for r, row := range x {
+		for i := 0; i < k; i++ {
+			xty[i] += row[i] * y[r]
+			for j := 0; j <= i; j++ {
+				xtx[i][j] += row[i] * row[j]
+			}
+		}
+	}
+	for i := 0; i < k; i++ {
+		for j := i + 1; j < k; j++ {
+			xtx[i][j] = xtx[j][i]
+		}
+	}
+
+	inv, ok := invert(xtx)
+	if !ok {
+		return res, false
+	}
+
+	beta := make([]float64, k)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			beta[i] += inv[i][j] * xty[j]
+		}
+	}
+
+	var rss float64
+	for r, row := range x {
+		e := y[r]
+		for i := 0; i < k; i++ {
+			e -= row[i] * beta[i]
+		}
+		rss += e * e
+	}
+
+	sigma2 := rss / float64(n-k)
+
+	stderr := make([]float64, k)
+	for i := range stderr {
+		stderr[i] = stdmath.Sqrt(sigma2 * inv[i][i])
+	}
+
+	return olsResult{beta: beta, stderr: stderr, rss: rss}, true
+}
+
+// invert inverts square matrix by Gauss-Jordan elimination with partial pivoting.
+func invert(a [][]float64) (inv [][]float64, ok bool) {
+	const eps = 1e-12
+
+	n := len(a)
+
+	m := make([][]float64, n)
+	inv = make([][]float64, n)
+	for i := range a {
+		m[i] = append([]float64(nil), a[i]...)
+		inv[i] = make([]float64, n)
+		inv[i][i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if stdmath.Abs(m[r][col]) > stdmath.Abs(m[pivot][col]) {
+				pivot = r
+			}
+		}
+
+		if stdmath.Abs(m[pivot][col]) < eps {
+			return nil, false
+		}
+
+		m[col], m[pivot] = m[pivot], m[col]
+		inv[col], inv[pivot] = inv[pivot], inv[col]
+
+		p := m[col][col]
+		for j := 0; j < n; j++ {
+			m[col][j] /= p
+			inv[col][j] /= p
+		}
+
+		for r := 0; r < n; r++ {
+			if r == col {
+				continue
+			}
+			f := m[r][col]
+			if f == 0 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				m[r][j] -= f * m[col][j]
+				inv[r][j] -= f * inv[col][j]
+			}
+		}
+	}
+
+	return inv, true
+}
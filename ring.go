@@ -0,0 +1,109 @@
+package fta
+
+// RingOHLCV is a fixed-capacity ohlcv for live use.
+// New bars evict the oldest ones, so memory usage doesn't grow with time.
+// The capacity must be not less than the longest window of indicators computed over the frame.
+type RingOHLCV struct {
+	freq int64
+	bars []Bar
+	head int
+	size int
+}
+
+// NewRingOHLCV returns empty ring of bars of freq size with given capacity.
+func NewRingOHLCV(capacity int, freq int64) *RingOHLCV {
+	if capacity <= 0 {
+		panic("capacity must be > 0")
+	}
+	return &RingOHLCV{
+		freq: freq,
+		bars: make([]Bar, capacity),
+	}
+}
+
+// Append adds the bar to the end evicting the oldest bar when the ring is full.
+// The bar with the same time as the last one replaces it, so the unfinished bar can be updated.
+// Bars older than the last one are dropped, false is returned then.
+func (r *RingOHLCV) Append(bar Bar) bool {
+	if r.size > 0 {
+		last := r.index(r.size - 1)
+
+		switch {
+		case bar.Time < r.bars[last].Time:
+			return false
+		case bar.Time == r.bars[last].Time:
+			r.bars[last] = bar
+			return true
+		}
+	}
+
+	if r.size < len(r.bars) {
+		r.bars[r.index(r.size)] = bar
+		r.size++
+		return true
+	}
+
+	r.bars[r.head] = bar
+	r.head = (r.head + 1) % len(r.bars)
+
+	return true
+}
+
+// Len returns the number of bars.
+func (r *RingOHLCV) Len() int {
+	return r.size
+}
+
+// Cap returns the capacity.
+func (r *RingOHLCV) Cap() int {
+	return len(r.bars)
+}
+
+// Full reports whether the ring is full, i.e. the oldest bar is evicted on the next append.
+func (r *RingOHLCV) Full() bool {
+	return r.size == len(r.bars)
+}
+
+// Bar returns bar at i position from the oldest one. Negative i counts from the last bar.
+func (r *RingOHLCV) Bar(i int) Bar {
+	if i < 0 {
+		i += r.size
+	}
+	if i < 0 || i >= r.size {
+		panic("index out of range")
+	}
+	return r.bars[r.index(i)]
+}
+
+// OHLCV returns copy of bars in chronological order.
+func (r *RingOHLCV) OHLCV() OHLCV {
+	return r.Last(r.size)
+}
+
+// Last returns copy of n last bars in chronological order.
+// All bars are returned when n is greater than the number of bars.
+func (r *RingOHLCV) Last(n int) OHLCV {
+	if n > r.size {
+		n = r.size
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	builder := newOHLCVBuilder(n)
+	for i := r.size - n; i < r.size; i++ {
+		builder.append(r.bars[r.index(i)])
+	}
+
+	return builder.build(r.freq)
+}
+
+// Reset removes all bars.
+func (r *RingOHLCV) Reset() {
+	r.head = 0
+	r.size = 0
+}
+
+func (r *RingOHLCV) index(i int) int {
+	return (r.head + i) % len(r.bars)
+}
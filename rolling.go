@@ -0,0 +1,316 @@
+package fta
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// RollingQuantile returns q-th quantile of values over the rolling window using linear interpolation.
+// The first period-1 values are NaN.
+//
+// Windows are kept in two heaps, so the cost of each step is O(log period) instead of sorting the window.
+func RollingQuantile(column series.Data, period int, q DType) (quantiles series.Data) {
+	return rollingOrder(column, period, q, func(lo, hi, frac DType) DType {
+		return lo + frac*(hi-lo)
+	})
+}
+
+// rollingMedian is the same as column.Rolling(period).Median() but it doesn't sort every window.
+func rollingMedian(column series.Data, period int) series.Data {
+	return rollingOrder(column, period, 0.5, func(lo, hi, frac DType) DType {
+		if frac == 0 {
+			return lo
+		}
+		return (lo + hi) / 2
+	})
+}
+
+// rollingOrder computes rolling order statistic at q-th position of the sorted window.
+// The value is combined by fn from the neighbour order statistics and the fractional part of the position.
+//
+// It reproduces the results of series.Window: windows containing NaN values are sorted with NaNs first,
+// and the series of the period length is delegated to series.Window.
+func rollingOrder(column series.Data, period int, q DType, fn func(lo, hi, frac DType) DType) series.Data {
+	values := column.Values()
+
+	if len(values) < period {
+		return allNaN(column)
+	}
+
+	if len(values) == period {
+		return column.Rolling(period).Apply(func(window series.Data) DType {
+			return sortedOrder(window.Values(), q, fn)
+		})
+	}
+
+	var (
+		result = column.Clone()
+		dst    = result.Values()
+
+		w  = newWindowHeaps(period)
+		na int
+	)
+
+	for i, v := range values {
+		if series.IsNA(v) {
+			na++
+		} else {
+			w.insert(v)
+		}
+
+		if i >= period {
+			if old := values[i-period]; series.IsNA(old) {
+				na--
+			} else {
+				w.erase(old)
+			}
+		}
+
+		if i < period-1 {
+			dst[i] = math.NaN()
+			continue
+		}
+
+		if na > 0 {
+			dst[i] = sortedOrder(values[i-period+1:i+1], q, fn)
+			continue
+		}
+
+		pos := q * DType(period-1)
+		l := int(math.Floor(pos))
+
+		w.balance(l + 1)
+
+		lo, hi := w.orders()
+
+		dst[i] = fn(lo, hi, pos-DType(l))
+	}
+
+	return result
+}
+
+// sortedOrder sorts copy of values like series.Window does and returns the order statistic.
+func sortedOrder(values []DType, q DType, fn func(lo, hi, frac DType) DType) DType {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	sorted := append([]DType(nil), values...)
+	sort.Sort(series.DTypeSlice(sorted))
+
+	pos := q * DType(len(sorted)-1)
+	l := int(math.Floor(pos))
+
+	if l >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+
+	return fn(sorted[l], sorted[l+1], pos-DType(l))
+}
+
+// RollingMax returns maximum of values over the rolling window, NaN values are skipped.
+// The first period-1 values are NaN.
+//
+// It's the same as column.Rolling(period).Max() but a monotonic deque makes it O(n) regardless of the period.
+func RollingMax(column series.Data, period int) (highest series.Data) {
+	return DefaultBackend.RollingMax(column, period)
+}
+
+func goRollingMax(column series.Data, period int) (highest series.Data) {
+	return rollingExtreme(column, period, series.Max, func(a, b DType) bool { return a >= b })
+}
+
+// RollingMin returns minimum of values over the rolling window, NaN values are skipped.
+// The first period-1 values are NaN.
+//
+// It's the same as column.Rolling(period).Min() but a monotonic deque makes it O(n) regardless of the period.
+func RollingMin(column series.Data, period int) (lowest series.Data) {
+	return DefaultBackend.RollingMin(column, period)
+}
+
+func goRollingMin(column series.Data, period int) (lowest series.Data) {
+	return rollingExtreme(column, period, series.Min, func(a, b DType) bool { return a <= b })
+}
+
+// rollingExtreme keeps indices of the window values in deque ordered by dominates,
+// so the front is always the extreme value of the window.
+func rollingExtreme(column series.Data, period int, agg series.AggregateFunc, dominates func(a, b DType) bool) series.Data {
+	values := column.Values()
+
+	if len(values) < period {
+		return allNaN(column)
+	}
+
+	if len(values) == period {
+		return column.Rolling(period).Apply(agg)
+	}
+
+	var (
+		result = column.Clone()
+		dst    = result.Values()
+
+		deque = make([]int, 0, period)
+		head  int
+	)
+
+	for i, v := range values {
+		if !series.IsNA(v) {
+			for len(deque) > head && dominates(v, values[deque[len(deque)-1]]) {
+				deque = deque[:len(deque)-1]
+			}
+			deque = append(deque, i)
+		}
+
+		if head < len(deque) && deque[head] <= i-period {
+			head++
+		}
+
+		// Compact the deque instead of letting it grow with the series.
+		if head > period {
+			deque = append(deque[:0], deque[head:]...)
+			head = 0
+		}
+
+		switch {
+		case i < period-1:
+			dst[i] = math.NaN()
+		case head == len(deque):
+			dst[i] = math.NaN()
+		default:
+			dst[i] = values[deque[head]]
+		}
+	}
+
+	return result
+}
+
+// windowHeaps keeps values of the window split into lower and upper halves.
+// Removed values are deleted lazily when they reach the top of their heap.
+type windowHeaps struct {
+	lo, hi         dtypeHeap
+	loSize, hiSize int
+	delayed        map[DType]int
+	target         int
+}
+
+func newWindowHeaps(capacity int) *windowHeaps {
+	return &windowHeaps{
+		lo:      dtypeHeap{values: make([]DType, 0, capacity), less: func(a, b DType) bool { return a > b }},
+		hi:      dtypeHeap{values: make([]DType, 0, capacity), less: func(a, b DType) bool { return a < b }},
+		delayed: make(map[DType]int),
+	}
+}
+
+func (w *windowHeaps) insert(v DType) {
+	if w.lo.Len() == 0 || v <= w.lo.top() {
+		heap.Push(&w.lo, v)
+		w.loSize++
+	} else {
+		heap.Push(&w.hi, v)
+		w.hiSize++
+	}
+	w.rebalance()
+}
+
+func (w *windowHeaps) erase(v DType) {
+	w.delayed[v]++
+
+	if w.lo.Len() > 0 && v <= w.lo.top() {
+		w.loSize--
+		if v == w.lo.top() {
+			w.prune(&w.lo)
+		}
+	} else {
+		w.hiSize--
+		if w.hi.Len() > 0 && v == w.hi.top() {
+			w.prune(&w.hi)
+		}
+	}
+
+	w.rebalance()
+}
+
+// balance sets the number of values of the lower half.
+func (w *windowHeaps) balance(target int) {
+	w.target = target
+	w.rebalance()
+}
+
+func (w *windowHeaps) rebalance() {
+	for w.loSize > w.target && w.loSize > 0 {
+		heap.Push(&w.hi, heap.Pop(&w.lo))
+		w.loSize--
+		w.hiSize++
+		w.prune(&w.lo)
+	}
+
+	for w.loSize < w.target && w.hiSize > 0 {
+		heap.Push(&w.lo, heap.Pop(&w.hi))
+		w.hiSize--
+		w.loSize++
+		w.prune(&w.hi)
+	}
+}
+
+// orders returns the largest value of the lower half and the smallest value of the upper half.
+// The upper value equals the lower one when the upper half is empty.
+func (w *windowHeaps) orders() (lo, hi DType) {
+	lo = w.lo.top()
+	hi = lo
+	if w.hiSize > 0 {
+		hi = w.hi.top()
+	}
+	return lo, hi
+}
+
+func (w *windowHeaps) prune(h *dtypeHeap) {
+	for h.Len() > 0 {
+		v := h.top()
+
+		n := w.delayed[v]
+		if n == 0 {
+			return
+		}
+
+		if n == 1 {
+			delete(w.delayed, v)
+		} else {
+			w.delayed[v] = n - 1
+		}
+
+		heap.Pop(h)
+	}
+}
+
+// dtypeHeap implements heap.Interface ordered by less.
+type dtypeHeap struct {
+	values []DType
+	less   func(a, b DType) bool
+}
+
+func (h dtypeHeap) Len() int            { return len(h.values) }
+func (h dtypeHeap) Less(i, j int) bool  { return h.less(h.values[i], h.values[j]) }
+func (h dtypeHeap) Swap(i, j int)       { h.values[i], h.values[j] = h.values[j], h.values[i] }
+func (h *dtypeHeap) Push(x interface{}) { h.values = append(h.values, x.(DType)) }
+func (h dtypeHeap) top() DType          { return h.values[0] }
+
+func (h *dtypeHeap) Pop() interface{} {
+	n := len(h.values) - 1
+	v := h.values[n]
+	h.values = h.values[:n]
+	return v
+}
+
+// allNaN returns the clone of the series filled by NaNs, e.g. for series shorter than the rolling window:
+// they have no full windows, and series.Window would index out of the data.
+func allNaN(column series.Data) series.Data {
+	result := column.Clone()
+	values := result.Values()
+	for i := range values {
+		values[i] = math.NaN()
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// MinMaxScale returns copy of column linearly scaled to [0, 1] range by its minimum and maximum.
+func MinMaxScale(column series.Data) (scaled series.Data) {
+	return Rescale(column, 0, 1)
+}
+
+// Rescale returns copy of column linearly scaled to [lo, hi] range by its minimum and maximum.
+// Constant column follows DivByZero policy.
+func Rescale(column series.Data, lo, hi DType) (scaled series.Data) {
+	var (
+		lowest  = series.Min(column)
+		highest = series.Max(column)
+	)
+
+	scaled = column.Clone().Apply(func(v DType) DType {
+		return lo + divide(v-lowest, highest-lowest)*(hi-lo)
+	})
+
+	return scaled
+}
+
+// RobustScale returns copy of column centered by its median and scaled by its interquartile range.
+// Unlike z-score it's robust to outliers.
+func RobustScale(column series.Data) (scaled series.Data) {
+	var (
+		sorted = sortedNotNA(column.Values())
+		median = quantile(sorted, 0.5)
+		iqr    = quantile(sorted, 0.75) - quantile(sorted, 0.25)
+	)
+
+	scaled = column.Clone().Apply(func(v DType) DType {
+		return divide(v-median, iqr)
+	})
+
+	return scaled
+}
+
+// RollingMinMaxScale returns copy of column scaled to [0, 1] range by the rolling minimum and maximum.
+func RollingMinMaxScale(column series.Data, period int) (scaled series.Data) {
+	return RollingRescale(column, period, 0, 1)
+}
+
+// RollingRescale returns copy of column scaled to [lo, hi] range by the rolling minimum and maximum.
+func RollingRescale(column series.Data, period int, lo, hi DType) (scaled series.Data) {
+	var (
+		lowest  = RollingMin(column, period)
+		highest = RollingMax(column, period)
+	)
+
+	scaled = div(column.Clone().Sub(lowest), highest.Sub(lowest)).
+		MulScalar(hi - lo).
+		AddScalar(lo)
+
+	return scaled
+}
+
+// RollingRobustScale returns copy of column centered by the rolling median and scaled by the rolling interquartile range.
+func RollingRobustScale(column series.Data, period int) (scaled series.Data) {
+	var (
+		median = column.Rolling(period).Median()
+		iqr    = column.Rolling(period).Apply(func(window series.Data) DType {
+			sorted := sortedNotNA(window.Values())
+			if len(sorted) == 0 {
+				return math.NaN()
+			}
+			return quantile(sorted, 0.75) - quantile(sorted, 0.25)
+		})
+	)
+
+	scaled = div(column.Clone().Sub(median), iqr)
+
+	return scaled
+}
@@ -0,0 +1,358 @@
+// Package screener runs indicator conditions across many symbols concurrently and ranks matches.
+//
+// Frames are loaded lazily by workers and released right after the last bars are evaluated,
+// so memory usage is bounded by the number of workers rather than the number of symbols:
+//
+//	screen := screener.Screen{
+//		Indicators: []screener.Indicator{
+//			{Name: "rsi", Compute: func(ohlcv fta.OHLCV) series.Data { return fta.RSI(ohlcv.Close, 14, false) }},
+//			{Name: "sma", Compute: func(ohlcv fta.OHLCV) series.Data { return fta.SMA(ohlcv.Close, 200) }},
+//		},
+//		Condition: alerts.MustParse("rsi < 30 and close > sma"),
+//		Rank:      alerts.MustParse("-rsi"),
+//		Limit:     20,
+//	}
+//
+//	matches, err := screen.Run(ctx, screener.PanelSource(panel))
+package screener
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/fta/alerts"
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// Source provides frames of symbols.
+// Load is called concurrently by workers.
+type Source interface {
+	Symbols() []string
+	Load(ctx context.Context, symbol string) (fta.OHLCV, error)
+}
+
+type panelSource fta.Panel
+
+// PanelSource returns the source of frames of the panel.
+func PanelSource(panel fta.Panel) Source {
+	return panelSource(panel)
+}
+
+func (p panelSource) Symbols() []string {
+	return fta.Panel(p).Symbols()
+}
+
+func (p panelSource) Load(ctx context.Context, symbol string) (fta.OHLCV, error) {
+	return p[symbol], nil
+}
+
+type funcSource struct {
+	symbols []string
+	load    func(ctx context.Context, symbol string) (fta.OHLCV, error)
+}
+
+// NewSource returns the source of symbols loading frames by the function, e.g. by store.Manager:
+//
+//	src := screener.NewSource(symbols, func(ctx context.Context, symbol string) (fta.OHLCV, error) {
+//		return manager.Get(ctx, symbol, time.Hour, from, to)
+//	})
+func NewSource(symbols []string, load func(ctx context.Context, symbol string) (fta.OHLCV, error)) Source {
+	return funcSource{symbols: symbols, load: load}
+}
+
+func (s funcSource) Symbols() []string {
+	return s.symbols
+}
+
+func (s funcSource) Load(ctx context.Context, symbol string) (fta.OHLCV, error) {
+	return s.load(ctx, symbol)
+}
+
+// Indicator is the named indicator computed for every symbol.
+type Indicator struct {
+	Name string
+	// Compute returns the indicator aligned to the close of ohlcv.
+	// Ohlcv is cloned before every computation, so it may be modified in place.
+	Compute func(ohlcv fta.OHLCV) series.Data
+}
+
+// Screen is the set of indicator conditions checked at the last bar of every symbol.
+type Screen struct {
+	Indicators []Indicator
+	// Condition is checked by values of the last two bars, builtin values are "open", "high", "low", "close" and "volume".
+	// Nil condition matches all symbols.
+	Condition alerts.Condition
+	// Rank is the score of matches, they are sorted by descending scores. Nil keeps matches sorted by symbols.
+	Rank *alerts.Expr
+	// Limit is the maximal number of returned matches, zero means no limit.
+	Limit int
+	// Lookback is the number of last bars used for computations, zero means all bars.
+	// It saves CPU when indicators need short history.
+	Lookback int
+	// Workers is the number of concurrent workers, zero means GOMAXPROCS.
+	Workers int
+	// OnError handles errors of loading symbols and computing indicators, nil means to stop screening.
+	// Panics of indicators, e.g. on frames shorter than their periods, are handled as errors too.
+	// Screening stops at the first error returned by OnError.
+	OnError func(symbol string, err error) error
+	// Progress receives the number of processed symbols, nil disables reporting.
+	Progress fta.ProgressFunc
+}
+
+// Match is the symbol satisfying the condition.
+type Match struct {
+	Symbol string
+	// Time is the time of the last bar.
+	Time int64
+	// Score is the value of Rank, NaN when there is no rank.
+	Score fta.DType
+	// Values are values of indicators and builtins at the last bar, they trigger the condition.
+	Values alerts.Values
+}
+
+var builtins = []string{"open", "high", "low", "close", "volume"}
+
+type result struct {
+	symbol string
+	match  *Match
+	err    error
+}
+
+// Run screens all symbols of the source and returns matches.
+func (s Screen) Run(ctx context.Context, src Source) ([]Match, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	workers := s.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		symbols = src.Symbols()
+		jobs    = make(chan string)
+		results = make(chan result)
+		wg      sync.WaitGroup
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for symbol := range jobs {
+				match, err := s.check(ctx, src, symbol)
+				select {
+				case results <- result{symbol: symbol, match: match, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, symbol := range symbols {
+			select {
+			case jobs <- symbol:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var (
+		matches []Match
+		err     error
+
+		tracker = fta.NewProgressTracker(s.Progress, int64(len(symbols)))
+	)
+
+	for r := range results {
+		tracker.Add(1)
+
+		if err != nil {
+			continue
+		}
+
+		if r.err != nil {
+			err = fmt.Errorf("screener: %s: %w", r.symbol, r.err)
+			if s.OnError != nil {
+				err = s.OnError(r.symbol, r.err)
+			}
+			if err != nil {
+				cancel()
+			}
+			continue
+		}
+
+		if r.match != nil {
+			matches = append(matches, *r.match)
+		}
+	}
+
+	tracker.Finish()
+
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.sort(matches)
+
+	if s.Limit > 0 && len(matches) > s.Limit {
+		matches = matches[:s.Limit]
+	}
+
+	return matches, nil
+}
+
+// check loads the symbol and evaluates the condition at its last bar.
+func (s Screen) check(ctx context.Context, src Source, symbol string) (*Match, error) {
+	ohlcv, err := src.Load(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	n := ohlcv.Len()
+	if n == 0 {
+		return nil, nil
+	}
+
+	if s.Lookback > 0 && n > s.Lookback {
+		ohlcv = ohlcv.Slice(n-s.Lookback, n)
+		n = s.Lookback
+	}
+
+	var (
+		cur  = barValues(ohlcv.Bar(n - 1))
+		prev alerts.Values
+	)
+
+	if n > 1 {
+		prev = barValues(ohlcv.Bar(n - 2))
+	}
+
+	for _, ind := range s.Indicators {
+		out, err := compute(ind, ohlcv)
+		if err != nil {
+			return nil, err
+		}
+
+		cur[ind.Name] = valueAt(out, len(out)-1)
+		if prev != nil {
+			prev[ind.Name] = valueAt(out, len(out)-2)
+		}
+	}
+
+	if s.Condition != nil && !s.Condition.Check(cur, prev) {
+		return nil, nil
+	}
+
+	match := &Match{
+		Symbol: symbol,
+		Time:   ohlcv.Close.IndexAt(n - 1),
+		Score:  math.NaN(),
+		Values: cur,
+	}
+
+	if s.Rank != nil {
+		match.Score = s.Rank.Eval(cur, prev)
+	}
+
+	return match, nil
+}
+
+// compute returns values of the indicator of the clone of ohlcv.
+// Indicators panic on invalid input, e.g. periods longer than the frame, so panics are returned as errors.
+func compute(ind Indicator, ohlcv fta.OHLCV) (out []fta.DType, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			out, err = nil, fmt.Errorf("indicator %q: %v", ind.Name, r)
+		}
+	}()
+
+	return ind.Compute(ohlcv.Clone()).Values(), nil
+}
+
+// sort sorts matches by descending scores, NaN scores are the last. Ties are sorted by symbols.
+func (s Screen) sort(matches []Match) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i].Score, matches[j].Score
+
+		switch {
+		case s.Rank == nil || a == b || (series.IsNA(a) && series.IsNA(b)):
+			return matches[i].Symbol < matches[j].Symbol
+		case series.IsNA(a):
+			return false
+		case series.IsNA(b):
+			return true
+		default:
+			return a > b
+		}
+	})
+}
+
+func (s Screen) validate() error {
+	known := map[string]bool{}
+	for _, name := range builtins {
+		known[name] = true
+	}
+	for _, ind := range s.Indicators {
+		if known[ind.Name] {
+			return fmt.Errorf("screener: duplicate value %q", ind.Name)
+		}
+		known[ind.Name] = true
+	}
+
+	var exprs []*alerts.Expr
+	if expr, ok := s.Condition.(*alerts.Expr); ok {
+		exprs = append(exprs, expr)
+	}
+	if s.Rank != nil {
+		exprs = append(exprs, s.Rank)
+	}
+
+	for _, expr := range exprs {
+		for _, name := range expr.Vars() {
+			if !known[name] {
+				return fmt.Errorf("screener: %q: unknown value %q", expr, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func barValues(bar fta.Bar) alerts.Values {
+	return alerts.Values{
+		"open":   bar.Open,
+		"high":   bar.High,
+		"low":    bar.Low,
+		"close":  bar.Close,
+		"volume": bar.Volume,
+	}
+}
+
+func valueAt(values []fta.DType, i int) fta.DType {
+	if i < 0 || i >= len(values) {
+		return math.NaN()
+	}
+	return values[i]
+}
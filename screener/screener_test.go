@@ -0,0 +1,109 @@
+package screener
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/fta/alerts"
+	"github.com/WinPooh32/series"
+)
+
+func smaIndicator(period int) Indicator {
+	return Indicator{
+		Name:    "sma",
+		Compute: func(ohlcv fta.OHLCV) series.Data { return fta.SMA(ohlcv.Close, period) },
+	}
+}
+
+func TestRunRanksMatches(t *testing.T) {
+	panel := fta.Panel{}
+	for i, symbol := range []string{"A", "B", "C", "D", "E"} {
+		panel[symbol] = fta.Generate(fta.GBM, fta.GenerateParams{Price: float64(10 * (i + 1))}, 100, int64(i))
+	}
+
+	screen := Screen{
+		Indicators: []Indicator{smaIndicator(20)},
+		Condition:  alerts.MustParse("sma > 0"),
+		Rank:       alerts.MustParse("close"),
+		Limit:      3,
+		Workers:    2,
+	}
+
+	matches, err := screen.Run(context.Background(), PanelSource(panel))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 3 {
+		t.Fatalf("got %d matches, want 3", len(matches))
+	}
+
+	for i, want := range []string{"E", "D", "C"} {
+		m := matches[i]
+		if m.Symbol != want {
+			t.Fatalf("match %d is %s, want %s", i, m.Symbol, want)
+		}
+		if m.Score != m.Values["close"] {
+			t.Fatalf("%s: score %v, want close %v", m.Symbol, m.Score, m.Values["close"])
+		}
+		if m.Time != panel[m.Symbol].Close.IndexAt(99) {
+			t.Fatalf("%s: time %d isn't the last bar", m.Symbol, m.Time)
+		}
+	}
+}
+
+func TestRunShortFrame(t *testing.T) {
+	panel := fta.Panel{
+		"long":  fta.Generate(fta.GBM, fta.GenerateParams{}, 300, 1),
+		"short": fta.Generate(fta.GBM, fta.GenerateParams{}, 50, 2),
+	}
+
+	screen := Screen{Indicators: []Indicator{smaIndicator(200)}}
+
+	if _, err := screen.Run(context.Background(), PanelSource(panel)); err == nil || !strings.Contains(err.Error(), "short") {
+		t.Fatalf("got error %v, want error of the short symbol", err)
+	}
+
+	var (
+		mu     sync.Mutex
+		failed []string
+	)
+
+	screen.OnError = func(symbol string, err error) error {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = append(failed, symbol)
+		return nil
+	}
+
+	matches, err := screen.Run(context.Background(), PanelSource(panel))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 1 || matches[0].Symbol != "long" {
+		t.Fatalf("got matches %+v, want the long symbol", matches)
+	}
+	if len(failed) != 1 || failed[0] != "short" {
+		t.Fatalf("got failed %v, want [short]", failed)
+	}
+}
+
+func TestRunValidates(t *testing.T) {
+	tests := map[string]Screen{
+		"duplicate": {Indicators: []Indicator{smaIndicator(5), smaIndicator(10)}},
+		"builtin":   {Indicators: []Indicator{{Name: "close", Compute: smaIndicator(5).Compute}}},
+		"unknown":   {Condition: alerts.MustParse("rsi < 30")},
+	}
+
+	for name, screen := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := screen.Run(context.Background(), PanelSource(fta.Panel{})); err == nil {
+				t.Fatal("got nil error")
+			}
+		})
+	}
+}
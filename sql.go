@@ -0,0 +1,321 @@
+//go:build !js
+// +build !js
+
+package fta
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/WinPooh32/series/math"
+)
+
+// SQLQueryer is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type SQLQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SQLExecer is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type SQLExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// SQLMapping maps ohlcv fields to names of table columns.
+// Zero value of names means DefaultSQLMapping names.
+type SQLMapping struct {
+	Time, Open, High, Low, Close, Volume string
+	// UnixTime is the unit of integer timestamps.
+	UnixTime UnixTime
+}
+
+// DefaultSQLMapping is the default names of table columns.
+var DefaultSQLMapping = SQLMapping{
+	Time:   "time",
+	Open:   "open",
+	High:   "high",
+	Low:    "low",
+	Close:  "close",
+	Volume: "volume",
+}
+
+// SQLPlaceholder is the style of query parameters placeholders.
+type SQLPlaceholder int
+
+const (
+	// PlaceholderQuestion is the '?' style of MySQL and SQLite.
+	PlaceholderQuestion SQLPlaceholder = iota
+	// PlaceholderDollar is the '$1' style of PostgreSQL.
+	PlaceholderDollar
+)
+
+// SQLWriteOptions configures WriteSQL.
+type SQLWriteOptions struct {
+	// BatchSize is the number of rows inserted by a single statement, zero value means 500.
+	BatchSize int
+	// Placeholder is the style of query parameters placeholders.
+	Placeholder SQLPlaceholder
+	// EpochTime writes timestamps as integers in units of mapping's UnixTime instead of time.Time.
+	EpochTime bool
+}
+
+// ReadSQL reads ohlcv from rows returned by the query.
+// Result columns are found by names of mapping, other columns are ignored.
+// Timestamps can be time.Time, integer epoch in units of mapping's UnixTime or strings in formats accepted by AutoTime.
+// Rows must be ordered by time. The frequency is detected as the most frequent interval between rows.
+func ReadSQL(ctx context.Context, db SQLQueryer, query string, mapping SQLMapping, args ...interface{}) (ohlcv OHLCV, err error) {
+	mapping = mapping.WithDefaults()
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return ohlcv, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return ohlcv, fmt.Errorf("columns: %w", err)
+	}
+
+	positions, err := mapping.resolve(names)
+	if err != nil {
+		return ohlcv, err
+	}
+
+	var (
+		raw     = make([]interface{}, len(names))
+		dest    = make([]interface{}, len(names))
+		builder = newOHLCVBuilder(0)
+	)
+
+	for i := range raw {
+		dest[i] = &raw[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return ohlcv, fmt.Errorf("scan: %w", err)
+		}
+
+		var bar Bar
+
+		bar.Time, err = mapping.scanTime(raw[positions[0]])
+		if err != nil {
+			return ohlcv, err
+		}
+
+		fields := []*DType{&bar.Open, &bar.High, &bar.Low, &bar.Close, &bar.Volume}
+		for i, field := range fields {
+			*field, err = scanFloat(raw[positions[i+1]])
+			if err != nil {
+				return ohlcv, fmt.Errorf("column %q: %w", names[positions[i+1]], err)
+			}
+		}
+
+		builder.append(bar)
+	}
+
+	if err := rows.Err(); err != nil {
+		return ohlcv, fmt.Errorf("rows: %w", err)
+	}
+
+	ohlcv = builder.build(detectFreq(builder.t))
+
+	return ohlcv, nil
+}
+
+// WriteSQL inserts ohlcv rows into the table by batches.
+// Pass *sql.Tx to make the whole write atomic.
+// Names of the table and columns are inserted into the query as is, so they must be trusted.
+func WriteSQL(ctx context.Context, db SQLExecer, table string, mapping SQLMapping, ohlcv OHLCV, opts SQLWriteOptions) error {
+	const defaultBatchSize = 500
+
+	mapping = mapping.WithDefaults()
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	head := fmt.Sprintf("INSERT INTO %s (%s, %s, %s, %s, %s, %s) VALUES ",
+		table, mapping.Time, mapping.Open, mapping.High, mapping.Low, mapping.Close, mapping.Volume)
+
+	var (
+		sb   strings.Builder
+		args = make([]interface{}, 0, batchSize*6)
+	)
+
+	for begin := 0; begin < ohlcv.Len(); begin += batchSize {
+		end := begin + batchSize
+		if end > ohlcv.Len() {
+			end = ohlcv.Len()
+		}
+
+		sb.Reset()
+		sb.WriteString(head)
+		args = args[:0]
+
+		for i := begin; i < end; i++ {
+			if i > begin {
+				sb.WriteString(", ")
+			}
+
+			sb.WriteByte('(')
+			for j := 0; j < 6; j++ {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+				switch opts.Placeholder {
+				case PlaceholderDollar:
+					sb.WriteByte('$')
+					sb.WriteString(strconv.Itoa(len(args) + j + 1))
+				default:
+					sb.WriteByte('?')
+				}
+			}
+			sb.WriteByte(')')
+
+			bar := ohlcv.Bar(i)
+
+			var ts interface{}
+			if opts.EpochTime {
+				ts = mapping.epoch(bar.Time)
+			} else {
+				ts = time.Unix(0, bar.Time).UTC()
+			}
+
+			args = append(args,
+				ts,
+				float64(bar.Open),
+				float64(bar.High),
+				float64(bar.Low),
+				float64(bar.Close),
+				float64(bar.Volume),
+			)
+		}
+
+		if _, err := db.ExecContext(ctx, sb.String(), args...); err != nil {
+			return fmt.Errorf("insert rows %d-%d: %w", begin, end, err)
+		}
+	}
+
+	return nil
+}
+
+// WithDefaults returns copy of mapping with empty names replaced by DefaultSQLMapping names.
+func (mapping SQLMapping) WithDefaults() SQLMapping {
+	fields := []struct {
+		name *string
+		def  string
+	}{
+		{&mapping.Time, DefaultSQLMapping.Time},
+		{&mapping.Open, DefaultSQLMapping.Open},
+		{&mapping.High, DefaultSQLMapping.High},
+		{&mapping.Low, DefaultSQLMapping.Low},
+		{&mapping.Close, DefaultSQLMapping.Close},
+		{&mapping.Volume, DefaultSQLMapping.Volume},
+	}
+
+	for _, f := range fields {
+		if *f.name == "" {
+			*f.name = f.def
+		}
+	}
+
+	return mapping
+}
+
+// resolve returns positions of time, open, high, low, close and volume columns.
+func (mapping SQLMapping) resolve(names []string) (positions [6]int, err error) {
+	fields := []string{mapping.Time, mapping.Open, mapping.High, mapping.Low, mapping.Close, mapping.Volume}
+
+	for i, field := range fields {
+		positions[i] = -1
+		for j, name := range names {
+			if strings.EqualFold(name, field) {
+				positions[i] = j
+				break
+			}
+		}
+		if positions[i] < 0 {
+			return positions, fmt.Errorf("column %q not found", field)
+		}
+	}
+
+	return positions, nil
+}
+
+func (mapping SQLMapping) scanTime(v interface{}) (int64, error) {
+	opts := CSVOptions{UnixTime: mapping.UnixTime}
+
+	switch t := v.(type) {
+	case time.Time:
+		return t.UnixNano(), nil
+	case int64:
+		return opts.ParseTime(strconv.FormatInt(t, 10))
+	case []byte:
+		return mapping.parseText(string(t))
+	case string:
+		return mapping.parseText(t)
+	case nil:
+		return 0, errors.New("time is null")
+	default:
+		return 0, fmt.Errorf("unsupported type of time %T", v)
+	}
+}
+
+// parseText parses textual timestamps like DATETIME of SQLite as epoch in units of UnixTime
+// falling back to layouts of AutoTime.
+func (mapping SQLMapping) parseText(field string) (int64, error) {
+	ts, err := CSVOptions{UnixTime: mapping.UnixTime}.ParseTime(field)
+	if err != nil && mapping.UnixTime != AutoTime {
+		if ts, autoErr := parseAutoTime(field); autoErr == nil {
+			return ts, nil
+		}
+	}
+	return ts, err
+}
+
+func (mapping SQLMapping) epoch(ts int64) int64 {
+	switch mapping.UnixTime {
+	case Seconds:
+		return ts / int64(time.Second)
+	case Milliseconds:
+		return ts / int64(time.Millisecond)
+	case Microseconds:
+		return ts / int64(time.Microsecond)
+	default:
+		return ts
+	}
+}
+
+func scanFloat(v interface{}) (DType, error) {
+	switch f := v.(type) {
+	case float64:
+		return DType(f), nil
+	case float32:
+		return DType(f), nil
+	case int64:
+		return DType(f), nil
+	case []byte:
+		return parseFloat(string(f))
+	case string:
+		return parseFloat(f)
+	case nil:
+		return math.NaN(), nil
+	default:
+		return 0, fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+func parseFloat(s string) (DType, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse float: %w", err)
+	}
+	return DType(f), nil
+}
@@ -0,0 +1,205 @@
+//go:build !js
+// +build !js
+
+package fta
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memTable is the single in-memory table of memConnector.
+// Inserts take column names from the query, queries return all rows with the extra id column.
+type memTable struct {
+	columns []string
+	rows    [][]driver.Value
+	queries []string
+}
+
+type memConnector struct{ table *memTable }
+
+func (c memConnector) Connect(context.Context) (driver.Conn, error) { return memConn(c), nil }
+func (c memConnector) Driver() driver.Driver                        { return nil }
+
+type memConn struct{ table *memTable }
+
+func (c memConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c memConn) Close() error                        { return nil }
+func (c memConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c memConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.table.queries = append(c.table.queries, query)
+
+	columns := strings.Split(query[strings.Index(query, "(")+1:strings.Index(query, ")")], ", ")
+	if c.table.columns == nil {
+		c.table.columns = columns
+	}
+	if len(args)%len(columns) != 0 {
+		return nil, errors.New("wrong number of args")
+	}
+
+	for i := 0; i < len(args); i += len(columns) {
+		row := make([]driver.Value, len(columns))
+		for j := range row {
+			row[j] = args[i+j].Value
+		}
+		c.table.rows = append(c.table.rows, row)
+	}
+
+	return driver.RowsAffected(len(args) / len(columns)), nil
+}
+
+func (c memConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &memRows{table: c.table}, nil
+}
+
+type memRows struct {
+	table *memTable
+	next  int
+}
+
+func (r *memRows) Columns() []string { return append([]string{"id"}, r.table.columns...) }
+func (r *memRows) Close() error      { return nil }
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if r.next == len(r.table.rows) {
+		return io.EOF
+	}
+	dest[0] = int64(r.next)
+	copy(dest[1:], r.table.rows[r.next])
+	r.next++
+	return nil
+}
+
+// openMemDB returns the database of the single empty table.
+func openMemDB(t *testing.T) (*sql.DB, *memTable) {
+	table := &memTable{}
+	db := sql.OpenDB(memConnector{table: table})
+	t.Cleanup(func() { db.Close() })
+	return db, table
+}
+
+// sqlBars returns n bars of one minute.
+func sqlBars(n int) OHLCV {
+	builder := newOHLCVBuilder(n)
+	for i := 0; i < n; i++ {
+		v := DType(i)
+		builder.append(Bar{Time: int64(i+1) * int64(time.Minute), Open: v, High: v + 2, Low: v - 1, Close: v + 1, Volume: 10 * v})
+	}
+	return builder.build(int64(time.Minute))
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		db, table = openMemDB(t)
+		ohlcv     = sqlBars(5)
+	)
+
+	err := WriteSQL(ctx, db, "bars", SQLMapping{}, ohlcv, SQLWriteOptions{BatchSize: 2, Placeholder: PlaceholderDollar})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(table.queries) != 3 {
+		t.Fatalf("got %d inserts, want 3 batches", len(table.queries))
+	}
+	const want = "INSERT INTO bars (time, open, high, low, close, volume) VALUES " +
+		"($1, $2, $3, $4, $5, $6), ($7, $8, $9, $10, $11, $12)"
+	if table.queries[0] != want {
+		t.Fatalf("got query %s, want %s", table.queries[0], want)
+	}
+
+	if _, ok := table.rows[0][0].(time.Time); !ok {
+		t.Fatalf("got time of type %T, want time.Time", table.rows[0][0])
+	}
+
+	got, err := ReadSQL(ctx, db, "SELECT * FROM bars ORDER BY time", SQLMapping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOHLCV(t, got, ohlcv)
+}
+
+func TestSQLEpochTime(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		db, table = openMemDB(t)
+		ohlcv     = sqlBars(3)
+		mapping   = SQLMapping{Time: "ts", Close: "c", Volume: "vol", UnixTime: Milliseconds}
+	)
+
+	err := WriteSQL(ctx, db, "bars", mapping, ohlcv, SQLWriteOptions{EpochTime: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(table.queries) != 1 || !strings.HasPrefix(table.queries[0], "INSERT INTO bars (ts, open, high, low, c, vol) VALUES (?, ?, ?, ?, ?, ?), ") {
+		t.Fatalf("got queries %q", table.queries)
+	}
+	if ts := table.rows[0][0]; ts != int64(60000) {
+		t.Fatalf("got time %v of type %T, want 60000 ms", ts, ts)
+	}
+
+	got, err := ReadSQL(ctx, db, "SELECT * FROM bars", mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOHLCV(t, got, ohlcv)
+}
+
+func TestReadSQLValues(t *testing.T) {
+	db, table := openMemDB(t)
+
+	// Textual timestamps and numbers like SQLite returns them, the null is NaN.
+	table.columns = []string{"TIME", "open", "high", "low", "close", "volume"}
+	table.rows = [][]driver.Value{
+		{"2023-01-02 03:04:00", []byte("1"), 3.0, int64(0), "2", nil},
+		{[]byte("1672628700"), 2.0, 4.0, 1.5, 3.0, 11.0},
+	}
+
+	ohlcv, err := ReadSQL(context.Background(), db, "SELECT * FROM bars", SQLMapping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2023, 1, 2, 3, 4, 0, 0, time.UTC).UnixNano()
+
+	if ohlcv.Len() != 2 || ohlcv.Bar(0).Time != start || ohlcv.Bar(1).Time != start+int64(time.Minute) {
+		t.Fatalf("got index %v", ohlcv.Close.Index())
+	}
+	assertValues(t, "open", ohlcv.Open, []DType{1, 2})
+	assertValues(t, "low", ohlcv.Low, []DType{0, 1.5})
+	assertValues(t, "close", ohlcv.Close, []DType{2, 3})
+	assertValues(t, "volume", ohlcv.Volume, []DType{nan, 11})
+
+	if _, err := ReadSQL(context.Background(), db, "SELECT * FROM bars", SQLMapping{Volume: "qty"}); err == nil || !strings.Contains(err.Error(), `"qty"`) {
+		t.Fatalf("got error %v, want missing column", err)
+	}
+
+	table.rows = append(table.rows, []driver.Value{nil, 1.0, 1.0, 1.0, 1.0, 1.0})
+	if _, err := ReadSQL(context.Background(), db, "SELECT * FROM bars", SQLMapping{}); err == nil {
+		t.Fatal("got no error of null time")
+	}
+}
+
+// assertOHLCV fails the test when bars or the frequency of got differ from want.
+func assertOHLCV(t *testing.T, got, want OHLCV) {
+	t.Helper()
+
+	if got.Close.Freq() != want.Close.Freq() {
+		t.Fatalf("got freq %d, want %d", got.Close.Freq(), want.Close.Freq())
+	}
+
+	bars := make([]Bar, want.Len())
+	for i := range bars {
+		bars[i] = want.Bar(i)
+	}
+	assertBars(t, got, bars)
+}
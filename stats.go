@@ -0,0 +1,210 @@
+package fta
+
+import (
+	"sort"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// CorrelationMethod is the method of computing correlation coefficient.
+type CorrelationMethod int
+
+const (
+	// Pearson is the standard linear correlation coefficient.
+	Pearson CorrelationMethod = iota
+	// Spearman is the rank correlation coefficient.
+	// It is robust to outliers and captures any monotonic relationship.
+	Spearman
+)
+
+// PctChange returns the fractional change between the current and a prior value, pct_change in pandas lingo.
+// The first period values are NaNs. Negative period compares with a later value like in pandas,
+// then the last -period values are NaNs.
+func PctChange(column series.Data, period int) (pct series.Data) {
+	pct = column.Clone()
+
+	values := pct.Values()
+	src := column.Values()
+
+	for i := range values {
+		j := i - period
+		if j < 0 || j >= len(src) {
+			values[i] = math.NaN()
+			continue
+		}
+		values[i] = src[i]/src[j] - 1
+	}
+
+	return pct
+}
+
+// ZScore returns the distance of values from the rolling mean measured in rolling standard deviations.
+func ZScore(column series.Data, period int) (zscore series.Data) {
+	ma := column.Rolling(period).Mean()
+	std := column.Rolling(period).Std(ma, 1)
+
+	zscore = column.Clone().Sub(ma).Div(std)
+
+	return zscore
+}
+
+// Correlation returns correlation coefficient of two series of the same length.
+// Pairs with NaN values are skipped.
+func Correlation(a, b series.Data, method CorrelationMethod) DType {
+	return correlation(a.Values(), b.Values(), method)
+}
+
+// RollingCorrelation returns correlation coefficient of two series of the same length over the rolling window.
+func RollingCorrelation(a, b series.Data, period int, method CorrelationMethod) (corr series.Data) {
+	corr = a.Clone()
+
+	var (
+		values = corr.Values()
+		av     = a.Values()
+		bv     = b.Values()
+	)
+
+	for i := range values {
+		if i < period-1 {
+			values[i] = math.NaN()
+			continue
+		}
+		values[i] = correlation(av[i-period+1:i+1], bv[i-period+1:i+1], method)
+	}
+
+	return corr
+}
+
+// CorrelationMatrix returns pairwise correlation matrix of close-to-close returns of the panel symbols.
+// Returns are calculated over period bars on the timestamps shared by all symbols.
+// Rows and columns of the matrix are ordered as the returned symbols.
+func CorrelationMatrix(panel Panel, period int, method CorrelationMethod) (symbols []string, matrix [][]DType) {
+	symbols = panel.Symbols()
+	returns := panelReturns(panel, symbols, period)
+
+	matrix = make([][]DType, len(symbols))
+	for i := range matrix {
+		matrix[i] = make([]DType, len(symbols))
+		matrix[i][i] = 1
+
+		for j := 0; j < i; j++ {
+			corr := Correlation(returns[i], returns[j], method)
+			matrix[i][j] = corr
+			matrix[j][i] = corr
+		}
+	}
+
+	return symbols, matrix
+}
+
+// RollingCorrelationMatrix is the rolling variant of CorrelationMatrix.
+// Every cell of the matrix is the correlation series over the rolling window.
+func RollingCorrelationMatrix(panel Panel, period, window int, method CorrelationMethod) (symbols []string, matrix [][]series.Data) {
+	symbols = panel.Symbols()
+	returns := panelReturns(panel, symbols, period)
+
+	matrix = make([][]series.Data, len(symbols))
+	for i := range matrix {
+		matrix[i] = make([]series.Data, len(symbols))
+		matrix[i][i] = returns[i].Clone().Apply(func(DType) DType { return 1 })
+
+		for j := 0; j < i; j++ {
+			corr := RollingCorrelation(returns[i], returns[j], window, method)
+			matrix[i][j] = corr
+			matrix[j][i] = corr
+		}
+	}
+
+	return symbols, matrix
+}
+
+func panelReturns(panel Panel, symbols []string, period int) []series.Data {
+	returns := panel.Closes(symbols)
+	for i, close := range returns {
+		returns[i] = PctChange(close, period)
+	}
+	return returns
+}
+
+func correlation(x, y []DType, method CorrelationMethod) DType {
+	var (
+		xs = make([]DType, 0, len(x))
+		ys = make([]DType, 0, len(y))
+	)
+
+	for i := range x {
+		if series.IsNA(x[i]) || series.IsNA(y[i]) {
+			continue
+		}
+		xs = append(xs, x[i])
+		ys = append(ys, y[i])
+	}
+
+	if method == Spearman {
+		xs = rank(xs)
+		ys = rank(ys)
+	}
+
+	return pearson(xs, ys)
+}
+
+func pearson(x, y []DType) DType {
+	n := len(x)
+	if n < 2 {
+		return math.NaN()
+	}
+
+	var meanX, meanY DType
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= DType(n)
+	meanY /= DType(n)
+
+	var cov, varX, varY DType
+	for i := range x {
+		dx := x[i] - meanX
+		dy := y[i] - meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+
+	if varX == 0 || varY == 0 {
+		return math.NaN()
+	}
+
+	return cov / math.Sqrt(varX*varY)
+}
+
+// rank returns ranks of values starting from 1, ties get the average rank.
+func rank(values []DType) []DType {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[order[i]] < values[order[j]]
+	})
+
+	ranks := make([]DType, len(values))
+
+	for i := 0; i < len(order); {
+		j := i + 1
+		for j < len(order) && values[order[j]] == values[order[i]] {
+			j++
+		}
+
+		avg := DType(i+j+1) / 2
+		for k := i; k < j; k++ {
+			ranks[order[k]] = avg
+		}
+
+		i = j
+	}
+
+	return ranks
+}
@@ -0,0 +1,244 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/WinPooh32/fta"
+)
+
+// Loader fetches bars of the symbol and interval with time in [from, to) range, e.g. from an exchange API.
+type Loader interface {
+	LoadBars(ctx context.Context, symbol string, interval time.Duration, from, to time.Time) (fta.OHLCV, error)
+}
+
+// LoaderFunc is the adapter to use ordinary function as Loader.
+type LoaderFunc func(ctx context.Context, symbol string, interval time.Duration, from, to time.Time) (fta.OHLCV, error)
+
+// LoadBars calls f(ctx, symbol, interval, from, to).
+func (f LoaderFunc) LoadBars(ctx context.Context, symbol string, interval time.Duration, from, to time.Time) (fta.OHLCV, error) {
+	return f(ctx, symbol, interval, from, to)
+}
+
+// Manager caches historical bars downloaded by the loader in stores of the directory.
+// Only ranges missing before the first and after the last stored bar are fetched, gaps inside are kept as is.
+// It's safe for concurrent use, stores of different symbols and intervals are downloaded concurrently.
+type Manager struct {
+	dir    string
+	loader Loader
+	opts   Options
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// entry is the store of a symbol and interval, its lock serializes downloads of the same store only.
+type entry struct {
+	mu    sync.Mutex
+	store *Store
+	// checked is the earliest time fetched before the first stored bar, so the empty range before
+	// the listing of the symbol isn't fetched again.
+	checked int64
+	known   bool
+}
+
+// NewManager returns the manager keeping stores in dir.
+func NewManager(dir string, loader Loader, opts Options) *Manager {
+	return &Manager{
+		dir:     dir,
+		loader:  loader,
+		opts:    opts,
+		entries: map[string]*entry{},
+	}
+}
+
+// Get returns bars of the symbol and interval with time in [from, to) range.
+// Missing ranges are fetched by the loader and stored first. Bars that aren't closed yet are never fetched,
+// so the range is limited by the start of the current bar.
+func (m *Manager) Get(ctx context.Context, symbol string, interval time.Duration, from, to time.Time) (fta.OHLCV, error) {
+	if now := time.Now().Truncate(interval); to.After(now) {
+		to = now
+	}
+
+	e := m.entry(symbol, interval)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.store == nil {
+		s, err := Open(symbolPath(m.dir, symbol, interval), int64(interval), m.opts)
+		if err != nil {
+			return fta.OHLCV{}, err
+		}
+		e.store = s
+	}
+
+	var (
+		start = from.UnixNano()
+		end   = to.UnixNano()
+	)
+
+	if start >= end {
+		return e.store.Load(start, end)
+	}
+
+	first, ok := e.store.First()
+	if !ok {
+		bars, err := m.fetch(ctx, symbol, interval, start, end)
+		if err != nil {
+			return fta.OHLCV{}, err
+		}
+		if err := e.store.Append(bars); err != nil {
+			return fta.OHLCV{}, fmt.Errorf("download %s %s: %w", symbol, interval, err)
+		}
+		e.checked, e.known = start, true
+		return e.store.Load(start, end)
+	}
+
+	lower := first
+	if e.known && e.checked < lower {
+		lower = e.checked
+	}
+
+	if start < lower {
+		bars, err := m.fetch(ctx, symbol, interval, start, lower)
+		if err != nil {
+			return fta.OHLCV{}, err
+		}
+		if err := m.prepend(symbol, interval, e, bars); err != nil {
+			return fta.OHLCV{}, fmt.Errorf("download %s %s: %w", symbol, interval, err)
+		}
+		e.checked, e.known = start, true
+	}
+
+	if last, _ := e.store.Last(); end > last+int64(interval) {
+		bars, err := m.fetch(ctx, symbol, interval, last+int64(interval), end)
+		if err != nil {
+			return fta.OHLCV{}, err
+		}
+		if err := e.store.Append(bars); err != nil {
+			return fta.OHLCV{}, fmt.Errorf("download %s %s: %w", symbol, interval, err)
+		}
+	}
+
+	return e.store.Load(start, end)
+}
+
+// Close closes all opened stores, they are opened again by following calls of Get.
+func (m *Manager) Close() (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range m.entries {
+		e.mu.Lock()
+		if e.store != nil {
+			if cerr := e.store.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+			e.store = nil
+		}
+		e.mu.Unlock()
+	}
+
+	return err
+}
+
+// entry returns the entry of the symbol and interval, the store is opened by the caller holding the entry lock.
+func (m *Manager) entry(symbol string, interval time.Duration) *entry {
+	path := symbolPath(m.dir, symbol, interval)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[path]
+	if !ok {
+		e = &entry{}
+		m.entries[path] = e
+	}
+
+	return e
+}
+
+// fetch loads bars in [from, to) range dropping bars out of the range and bars that aren't later than the previous one.
+func (m *Manager) fetch(ctx context.Context, symbol string, interval time.Duration, from, to int64) ([]fta.Bar, error) {
+	ohlcv, err := m.loader.LoadBars(ctx, symbol, interval, time.Unix(0, from), time.Unix(0, to))
+	if err != nil {
+		return nil, fmt.Errorf("download %s %s: %w", symbol, interval, err)
+	}
+
+	bars := make([]fta.Bar, 0, ohlcv.Len())
+
+	for i := 0; i < ohlcv.Len(); i++ {
+		bar := ohlcv.Bar(i)
+
+		if bar.Time < from || bar.Time >= to {
+			continue
+		}
+		if len(bars) > 0 && bar.Time <= bars[len(bars)-1].Time {
+			continue
+		}
+
+		bars = append(bars, bar)
+	}
+
+	return bars, nil
+}
+
+// prepend rewrites the store of the entry with bars before the stored ones, the file is replaced atomically by rename.
+func (m *Manager) prepend(symbol string, interval time.Duration, e *entry, bars []fta.Bar) error {
+	if len(bars) == 0 {
+		return nil
+	}
+
+	var (
+		path = symbolPath(m.dir, symbol, interval)
+		tmp  = path + ".tmp"
+	)
+
+	stored, err := e.store.LoadAll()
+	if err != nil {
+		return err
+	}
+
+	_ = os.Remove(tmp)
+
+	t, err := Open(tmp, int64(interval), m.opts)
+	if err != nil {
+		return err
+	}
+
+	if err := t.Append(bars); err != nil {
+		t.Close()
+		return err
+	}
+	if err := t.AppendOHLCV(stored); err != nil {
+		t.Close()
+		return err
+	}
+	if err := t.Sync(); err != nil {
+		t.Close()
+		return err
+	}
+	if err := t.Close(); err != nil {
+		return err
+	}
+
+	e.store.Close()
+	e.store = nil
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("store: replace: %w", err)
+	}
+
+	s, err := Open(path, int64(interval), m.opts)
+	if err != nil {
+		return err
+	}
+
+	e.store = s
+
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package store
+
+import (
+	"errors"
+	"os"
+)
+
+// mmap isn't supported, the store falls back to reading the file.
+func mmap(file *os.File, size int64) (data []byte, unmap func(), err error) {
+	return nil, nil, errors.New("store: mmap isn't supported")
+}
@@ -0,0 +1,328 @@
+// Package store persists bars in append-only binary files, one file per symbol and interval.
+// Loading a range of bars is a binary search and a single read, so it's much faster than parsing csv.
+//
+// The file starts with 16 bytes header: magic "FTAB", version byte, 3 reserved bytes and the frequency
+// in nanoseconds as little-endian int64. It's followed by 48 bytes records of time and open, high, low,
+// close, volume as little-endian int64 and float64 values. Records are sorted by time without duplicates.
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	stdmath "math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+)
+
+const (
+	magic      = "FTAB"
+	version    = 1
+	headerSize = 16
+	recordSize = 48
+)
+
+var (
+	// ErrFormat is returned when the file isn't a bar store or has unsupported version.
+	ErrFormat = errors.New("store: invalid file format")
+	// ErrFreq is returned when the frequency of the file differs from the requested one.
+	ErrFreq = errors.New("store: frequency mismatch")
+	// ErrOrder is returned when appended bars aren't later than the stored ones.
+	ErrOrder = errors.New("store: bars must be appended in increasing time order")
+)
+
+// Options are options of the store.
+type Options struct {
+	// Mmap maps the file into memory for loading instead of reading it.
+	// It's ignored on platforms without mmap.
+	Mmap bool
+}
+
+// Store is the append-only file of bars of one symbol and interval.
+// It's safe for concurrent use.
+type Store struct {
+	mu   sync.RWMutex
+	file *os.File
+	freq int64
+	opts Options
+
+	count int
+	first int64
+	last  int64
+}
+
+// Open opens the store file at path creating it if it doesn't exist.
+// A torn record at the end of the file left by interrupted append is truncated.
+func Open(path string, freq int64, opts Options) (*Store, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("store: open: %w", err)
+	}
+
+	s := &Store{file: file, freq: freq, opts: opts}
+
+	if err := s.init(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// OpenSymbol opens the store of symbol and interval in dir, the file is named like "BTCUSDT_1m0s.bars".
+func OpenSymbol(dir, symbol string, freq time.Duration, opts Options) (*Store, error) {
+	return Open(symbolPath(dir, symbol, freq), int64(freq), opts)
+}
+
+func symbolPath(dir, symbol string, freq time.Duration) string {
+	return filepath.Join(dir, fmt.Sprintf("%s_%s.bars", symbol, freq))
+}
+
+func (s *Store) init() error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return fmt.Errorf("store: stat: %w", err)
+	}
+
+	size := info.Size()
+
+	if size == 0 {
+		var header [headerSize]byte
+		copy(header[:], magic)
+		header[4] = version
+		binary.LittleEndian.PutUint64(header[8:], uint64(s.freq))
+
+		if _, err := s.file.WriteAt(header[:], 0); err != nil {
+			return fmt.Errorf("store: write header: %w", err)
+		}
+
+		return nil
+	}
+
+	var header [headerSize]byte
+	if _, err := s.file.ReadAt(header[:], 0); err != nil {
+		return ErrFormat
+	}
+
+	if string(header[:4]) != magic || header[4] != version {
+		return ErrFormat
+	}
+
+	if freq := int64(binary.LittleEndian.Uint64(header[8:])); freq != s.freq {
+		return fmt.Errorf("%w: file has %d, requested %d", ErrFreq, freq, s.freq)
+	}
+
+	s.count = int((size - headerSize) / recordSize)
+
+	if tail := headerSize + int64(s.count)*recordSize; tail != size {
+		if err := s.file.Truncate(tail); err != nil {
+			return fmt.Errorf("store: truncate torn record: %w", err)
+		}
+	}
+
+	if s.count > 0 {
+		if s.first, err = readTime(s.file, 0); err != nil {
+			return fmt.Errorf("store: read: %w", err)
+		}
+		if s.last, err = readTime(s.file, s.count-1); err != nil {
+			return fmt.Errorf("store: read: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of stored bars.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.count
+}
+
+// First returns time of the first stored bar, ok is false for the empty store.
+func (s *Store) First() (first int64, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.first, s.count > 0
+}
+
+// Last returns time of the last stored bar, ok is false for the empty store.
+func (s *Store) Last() (last int64, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last, s.count > 0
+}
+
+// Append writes bars at the end of the store. Bars must be sorted by time and be later than the last stored bar,
+// otherwise nothing is written and ErrOrder is returned.
+func (s *Store) Append(bars []fta.Bar) error {
+	if len(bars) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last, has := s.last, s.count > 0
+	buf := make([]byte, len(bars)*recordSize)
+
+	for i, bar := range bars {
+		if has && bar.Time <= last {
+			return fmt.Errorf("%w: bar at %d after %d", ErrOrder, bar.Time, last)
+		}
+		last, has = bar.Time, true
+
+		putRecord(buf[i*recordSize:], bar)
+	}
+
+	offset := headerSize + int64(s.count)*recordSize
+
+	if _, err := s.file.WriteAt(buf, offset); err != nil {
+		// Drop a partially written tail, so the file stays consistent.
+		_ = s.file.Truncate(offset)
+		return fmt.Errorf("store: append: %w", err)
+	}
+
+	if s.count == 0 {
+		s.first = bars[0].Time
+	}
+
+	s.count += len(bars)
+	s.last = last
+
+	return nil
+}
+
+// AppendOHLCV writes all bars of ohlcv at the end of the store like Append.
+func (s *Store) AppendOHLCV(ohlcv fta.OHLCV) error {
+	bars := make([]fta.Bar, ohlcv.Len())
+	for i := range bars {
+		bars[i] = ohlcv.Bar(i)
+	}
+	return s.Append(bars)
+}
+
+// Load returns bars with time in [from, to) range.
+func (s *Store) Load(from, to int64) (ohlcv fta.OHLCV, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var (
+		reader io.ReaderAt = s.file
+		size               = headerSize + int64(s.count)*recordSize
+	)
+
+	if s.opts.Mmap && s.count > 0 {
+		data, unmap, err := mmap(s.file, size)
+		if err == nil {
+			defer unmap()
+			reader = bytes.NewReader(data)
+		}
+	}
+
+	var searchErr error
+
+	search := func(ts int64) int {
+		return sort.Search(s.count, func(i int) bool {
+			t, err := readTime(reader, i)
+			if err != nil {
+				searchErr = err
+				return true
+			}
+			return t >= ts
+		})
+	}
+
+	var (
+		begin = search(from)
+		end   = search(to)
+	)
+
+	if searchErr != nil {
+		return ohlcv, fmt.Errorf("store: load: %w", searchErr)
+	}
+
+	if end < begin {
+		end = begin
+	}
+
+	buf := make([]byte, (end-begin)*recordSize)
+	if _, err := reader.ReadAt(buf, headerSize+int64(begin)*recordSize); err != nil && !(errors.Is(err, io.EOF) && len(buf) == 0) {
+		return ohlcv, fmt.Errorf("store: load: %w", err)
+	}
+
+	return decode(buf, s.freq), nil
+}
+
+// LoadAll returns all stored bars.
+func (s *Store) LoadAll() (fta.OHLCV, error) {
+	return s.Load(stdmath.MinInt64, stdmath.MaxInt64)
+}
+
+// Sync commits written bars to stable storage.
+func (s *Store) Sync() error {
+	return s.file.Sync()
+}
+
+// Close closes the store file.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+func putRecord(b []byte, bar fta.Bar) {
+	binary.LittleEndian.PutUint64(b[0:], uint64(bar.Time))
+	binary.LittleEndian.PutUint64(b[8:], stdmath.Float64bits(float64(bar.Open)))
+	binary.LittleEndian.PutUint64(b[16:], stdmath.Float64bits(float64(bar.High)))
+	binary.LittleEndian.PutUint64(b[24:], stdmath.Float64bits(float64(bar.Low)))
+	binary.LittleEndian.PutUint64(b[32:], stdmath.Float64bits(float64(bar.Close)))
+	binary.LittleEndian.PutUint64(b[40:], stdmath.Float64bits(float64(bar.Volume)))
+}
+
+func readTime(r io.ReaderAt, i int) (int64, error) {
+	var b [8]byte
+	if _, err := r.ReadAt(b[:], headerSize+int64(i)*recordSize); err != nil {
+		return 0, err
+	}
+	return int64(binary.LittleEndian.Uint64(b[:])), nil
+}
+
+// decode converts records to columns.
+func decode(buf []byte, freq int64) fta.OHLCV {
+	n := len(buf) / recordSize
+
+	var (
+		index = make([]int64, n)
+		cols  [5][]fta.DType
+	)
+
+	for k := range cols {
+		cols[k] = make([]fta.DType, n)
+	}
+
+	for i := 0; i < n; i++ {
+		rec := buf[i*recordSize:]
+
+		index[i] = int64(binary.LittleEndian.Uint64(rec))
+		for k := range cols {
+			cols[k][i] = fta.DType(stdmath.Float64frombits(binary.LittleEndian.Uint64(rec[8+8*k:])))
+		}
+	}
+
+	return fta.OHLCV{
+		Open:   series.MakeData(freq, index, cols[0]),
+		High:   series.MakeData(freq, append([]int64(nil), index...), cols[1]),
+		Low:    series.MakeData(freq, append([]int64(nil), index...), cols[2]),
+		Close:  series.MakeData(freq, append([]int64(nil), index...), cols[3]),
+		Volume: series.MakeData(freq, append([]int64(nil), index...), cols[4]),
+	}
+}
@@ -0,0 +1,189 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/WinPooh32/fta"
+)
+
+const minute = int64(time.Minute)
+
+// bars returns n bars of one minute starting at the minute.
+func bars(start, n int) []fta.Bar {
+	bb := make([]fta.Bar, n)
+	for i := range bb {
+		v := fta.DType(start + i)
+		bb[i] = fta.Bar{Time: int64(start+i) * minute, Open: v, High: v + 1, Low: v - 1, Close: v + 0.5, Volume: 10 * v}
+	}
+	return bb
+}
+
+func assertBars(t *testing.T, ohlcv fta.OHLCV, want []fta.Bar) {
+	t.Helper()
+
+	if ohlcv.Len() != len(want) {
+		t.Fatalf("got %d bars, want %d", ohlcv.Len(), len(want))
+	}
+	for i, w := range want {
+		if got := ohlcv.Bar(i); got != w {
+			t.Fatalf("bar %d: got %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestAppendLoad(t *testing.T) {
+	for _, opts := range []Options{{}, {Mmap: true}} {
+		path := filepath.Join(t.TempDir(), "bars")
+
+		s, err := Open(path, minute, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := s.Append(bars(0, 5)); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Append(bars(5, 5)); err != nil {
+			t.Fatal(err)
+		}
+
+		ohlcv, err := s.Load(3*minute, 7*minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertBars(t, ohlcv, bars(3, 4))
+
+		if ohlcv, err = s.Load(20*minute, 30*minute); err != nil {
+			t.Fatal(err)
+		}
+		assertBars(t, ohlcv, nil)
+
+		if first, ok := s.First(); !ok || first != 0 {
+			t.Fatalf("got first %d %v", first, ok)
+		}
+		if last, ok := s.Last(); !ok || last != 9*minute {
+			t.Fatalf("got last %d %v", last, ok)
+		}
+
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Reopened store continues from the stored bars.
+		if s, err = Open(path, minute, opts); err != nil {
+			t.Fatal(err)
+		}
+		if ohlcv, err = s.LoadAll(); err != nil {
+			t.Fatal(err)
+		}
+		assertBars(t, ohlcv, bars(0, 10))
+		s.Close()
+	}
+}
+
+func TestAppendOrder(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "bars"), minute, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := s.Append(bars(5, 3)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		bars []fta.Bar
+	}{
+		{"before last", bars(2, 2)},
+		{"same as last", bars(7, 2)},
+		{"unsorted", append(bars(10, 2), bars(9, 1)...)},
+	}
+
+	for _, tt := range tests {
+		if err := s.Append(tt.bars); !errors.Is(err, ErrOrder) {
+			t.Fatalf("%s: got error %v, want ErrOrder", tt.name, err)
+		}
+	}
+
+	// Rejected batches are not written partially.
+	ohlcv, err := s.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertBars(t, ohlcv, bars(5, 3))
+}
+
+func TestTornRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bars")
+
+	s, err := Open(path, minute, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Append(bars(0, 3)); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	// Interrupted append leaves a part of the record.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(make([]byte, recordSize/2)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if s, err = Open(path, minute, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if info, err := os.Stat(path); err != nil || info.Size() != headerSize+3*recordSize {
+		t.Fatalf("torn record isn't truncated: %v %v", info.Size(), err)
+	}
+
+	if err := s.Append(bars(3, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	ohlcv, err := s.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertBars(t, ohlcv, bars(0, 4))
+}
+
+func TestOpenErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := OpenSymbol(dir, "BTCUSDT", time.Minute, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	path := filepath.Join(dir, "BTCUSDT_1m0s.bars")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(path, int64(time.Hour), Options{}); !errors.Is(err, ErrFreq) {
+		t.Fatalf("got error %v, want ErrFreq", err)
+	}
+
+	path = filepath.Join(dir, "other")
+	if err := os.WriteFile(path, []byte("not a store file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, minute, Options{}); !errors.Is(err, ErrFormat) {
+		t.Fatalf("got error %v, want ErrFormat", err)
+	}
+}
@@ -0,0 +1,76 @@
+package stream
+
+import (
+	"github.com/WinPooh32/fta"
+)
+
+// BarBuilder aggregates trades into bars of fixed size.
+// Bars are labeled by the start of the interval, intervals without trades are skipped.
+type BarBuilder struct {
+	freq int64
+	bar  fta.Bar
+	open bool
+}
+
+// NewBarBuilder returns builder of bars of freq size in nanoseconds.
+func NewBarBuilder(freq int64) *BarBuilder {
+	return &BarBuilder{freq: freq}
+}
+
+// Add adds the trade to the current bar.
+// If the trade opens a new interval, the previous bar is returned as closed.
+// Late trades belonging to already closed bars are dropped.
+func (b *BarBuilder) Add(trade Trade) (bar fta.Bar, closed bool) {
+	start := trade.Time - mod(trade.Time, b.freq)
+
+	if b.open {
+		switch {
+		case start < b.bar.Time:
+			return bar, false
+		case start == b.bar.Time:
+			if trade.Price > b.bar.High {
+				b.bar.High = trade.Price
+			}
+			if trade.Price < b.bar.Low {
+				b.bar.Low = trade.Price
+			}
+			b.bar.Close = trade.Price
+			b.bar.Volume += trade.Size
+			return bar, false
+		}
+
+		bar, closed = b.bar, true
+	}
+
+	b.bar = fta.Bar{
+		Time:   start,
+		Open:   trade.Price,
+		High:   trade.Price,
+		Low:    trade.Price,
+		Close:  trade.Price,
+		Volume: trade.Size,
+	}
+	b.open = true
+
+	return bar, closed
+}
+
+// Current returns the unfinished bar.
+func (b *BarBuilder) Current() (bar fta.Bar, ok bool) {
+	return b.bar, b.open
+}
+
+// Flush closes the current bar and returns it.
+func (b *BarBuilder) Flush() (bar fta.Bar, ok bool) {
+	bar, ok = b.bar, b.open
+	b.bar, b.open = fta.Bar{}, false
+	return bar, ok
+}
+
+func mod(a, b int64) int64 {
+	m := a % b
+	if m < 0 {
+		m += b
+	}
+	return m
+}
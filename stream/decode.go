@@ -0,0 +1,173 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/WinPooh32/fta"
+)
+
+// JSONBarDecoder decodes bars from JSON objects, e.g. {"t":1600000000,"o":1,"h":2,"l":0.5,"c":1.5,"v":10}.
+// Values can be numbers or strings of numbers. Other keys are ignored.
+type JSONBarDecoder struct {
+	// Names are keys of ohlcv fields, zero value means t, o, h, l, c, v.
+	Names fta.CSVNames
+	// Options configures parsing of timestamps.
+	Options fta.CSVOptions
+}
+
+// Decode implements Decoder interface.
+func (d JSONBarDecoder) Decode(data []byte) (msg Message, err error) {
+	names := d.Names
+	if names == (fta.CSVNames{}) {
+		names = fta.CSVNames{Time: "t", Open: "o", High: "h", Low: "l", Close: "c", Volume: "v"}
+	}
+
+	obj, err := decodeObject(data)
+	if err != nil {
+		return msg, err
+	}
+
+	msg.Bar.Time, err = parseTime(obj, names.Time, d.Options)
+	if err != nil {
+		return msg, err
+	}
+
+	fields := []struct {
+		name  string
+		value *fta.DType
+	}{
+		{names.Open, &msg.Bar.Open},
+		{names.High, &msg.Bar.High},
+		{names.Low, &msg.Bar.Low},
+		{names.Close, &msg.Bar.Close},
+		{names.Volume, &msg.Bar.Volume},
+	}
+
+	for _, f := range fields {
+		if *f.value, err = parseFloat(obj, f.name); err != nil {
+			return msg, err
+		}
+	}
+
+	return msg, nil
+}
+
+// JSONTradeDecoder decodes trades from JSON objects, e.g. {"t":1600000000000,"p":"1.5","q":"0.1"}.
+// Values can be numbers or strings of numbers. Other keys are ignored.
+type JSONTradeDecoder struct {
+	// Time, Price and Size are keys of trade fields, zero values mean t, p, q.
+	Time, Price, Size string
+	// Options configures parsing of timestamps.
+	Options fta.CSVOptions
+}
+
+// Decode implements Decoder interface.
+func (d JSONTradeDecoder) Decode(data []byte) (msg Message, err error) {
+	obj, err := decodeObject(data)
+	if err != nil {
+		return msg, err
+	}
+
+	msg.IsTrade = true
+
+	msg.Trade.Time, err = parseTime(obj, orDefault(d.Time, "t"), d.Options)
+	if err != nil {
+		return msg, err
+	}
+
+	if msg.Trade.Price, err = parseFloat(obj, orDefault(d.Price, "p")); err != nil {
+		return msg, err
+	}
+
+	if msg.Trade.Size, err = parseFloat(obj, orDefault(d.Size, "q")); err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
+
+// CSVBarDecoder decodes bars from single csv records.
+// Header, Names and Freq of options are ignored.
+type CSVBarDecoder struct {
+	Options fta.CSVOptions
+}
+
+// Decode implements Decoder interface.
+func (d CSVBarDecoder) Decode(data []byte) (msg Message, err error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	if d.Options.Comma != 0 {
+		reader.Comma = d.Options.Comma
+	}
+
+	record, err := reader.Read()
+	if err != nil {
+		return msg, fmt.Errorf("read csv: %w", err)
+	}
+
+	columns := d.Options.Columns
+	if columns == (fta.CSVColumns{}) {
+		columns = fta.DefaultCSVColumns
+	}
+
+	msg.Bar, err = d.Options.ParseRecord(record, columns)
+
+	return msg, err
+}
+
+func decodeObject(data []byte) (obj map[string]json.RawMessage, err error) {
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("decode json: %w", err)
+	}
+	return obj, nil
+}
+
+// field returns the value of the key, strings are unquoted.
+func field(obj map[string]json.RawMessage, key string) (string, error) {
+	raw, ok := obj[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found", key)
+	}
+
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", fmt.Errorf("key %q: %w", key, err)
+		}
+		return s, nil
+	}
+
+	return string(raw), nil
+}
+
+func parseTime(obj map[string]json.RawMessage, key string, opts fta.CSVOptions) (int64, error) {
+	s, err := field(obj, key)
+	if err != nil {
+		return 0, err
+	}
+	return opts.ParseTime(s)
+}
+
+func parseFloat(obj map[string]json.RawMessage, key string) (fta.DType, error) {
+	s, err := field(obj, key)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse float: key %q: %w", key, err)
+	}
+
+	return fta.DType(f), nil
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
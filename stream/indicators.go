@@ -0,0 +1,120 @@
+package stream
+
+import (
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// SMA is the streaming simple moving average, see fta.SMA.
+type SMA struct {
+	period int
+	window []fta.DType
+	pos    int
+	count  int
+	sum    fta.DType
+}
+
+// NewSMA returns the streaming simple moving average.
+func NewSMA(period int) *SMA {
+	return &SMA{period: period, window: make([]fta.DType, period)}
+}
+
+// Update adds the value and returns the current average.
+// The average is NaN until period values are added.
+func (s *SMA) Update(v fta.DType) fta.DType {
+	if s.count == s.period {
+		s.sum -= s.window[s.pos]
+	} else {
+		s.count++
+	}
+
+	s.window[s.pos] = v
+	s.sum += v
+	s.pos = (s.pos + 1) % s.period
+
+	if s.count < s.period {
+		return math.NaN()
+	}
+
+	return s.sum / fta.DType(s.period)
+}
+
+// EMA is the streaming exponential moving average, see fta.EMA with adjust=false.
+type EMA struct {
+	alpha fta.DType
+	value fta.DType
+	ready bool
+}
+
+// NewEMA returns the streaming exponential moving average.
+func NewEMA(period int) *EMA {
+	return &EMA{alpha: 2 / (fta.DType(period) + 1)}
+}
+
+// NewSSMA returns the streaming smoothed moving average, see fta.SSMA with adjust=false.
+func NewSSMA(period int) *EMA {
+	return &EMA{alpha: 1 / fta.DType(period)}
+}
+
+// Update adds the value and returns the current average.
+// NaN values are skipped, leading NaN is replaced by zero like fta.EMA does.
+func (e *EMA) Update(v fta.DType) fta.DType {
+	switch {
+	case !e.ready:
+		e.value = v
+		if series.IsNA(v) {
+			e.value = 0
+		}
+		e.ready = true
+	case !series.IsNA(v):
+		e.value += e.alpha * (v - e.value)
+	}
+
+	return e.value
+}
+
+// RSI is the streaming relative strength index, see fta.RSI with adjust=false.
+type RSI struct {
+	gain, loss EMA
+	prev       fta.DType
+	ready      bool
+}
+
+// NewRSI returns the streaming relative strength index.
+func NewRSI(period int) *RSI {
+	return &RSI{
+		gain: *NewSSMA(period),
+		loss: *NewSSMA(period),
+	}
+}
+
+// Update adds the price and returns the current index.
+// The index is 100 without losses, flat prices follow fta.DivByZero policy.
+func (r *RSI) Update(v fta.DType) fta.DType {
+	diff := math.NaN()
+	if r.ready {
+		diff = v - r.prev
+	}
+
+	r.prev = v
+	r.ready = true
+
+	var up, down fta.DType
+
+	switch {
+	case math.IsNaN(diff):
+		up, down = diff, diff
+	case diff > 0:
+		up = diff
+	default:
+		down = -diff
+	}
+
+	var (
+		gain = r.gain.Update(up)
+		loss = r.loss.Update(down)
+	)
+
+	return 100 * fta.Divide(gain, gain+loss)
+}
@@ -0,0 +1,72 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+type updater interface {
+	Update(v fta.DType) fta.DType
+}
+
+// assertMatches fails the test when streaming values of the indicator differ from the batch ones.
+func assertMatches(t *testing.T, name string, ind updater, values []fta.DType, want series.Data) {
+	t.Helper()
+
+	for i, v := range values {
+		got, w := ind.Update(v), want.Values()[i]
+		if series.IsNA(got) && series.IsNA(w) {
+			continue
+		}
+		if series.IsNA(got) || series.IsNA(w) || math.Abs(got-w) > 1e-4*math.Max(1, math.Abs(w)) {
+			t.Fatalf("%s: at %d got %v, want %v", name, i, got, w)
+		}
+	}
+}
+
+func rising(n int) series.Data {
+	index := make([]int64, n)
+	values := make([]fta.DType, n)
+	for i := range values {
+		index[i] = int64(i)
+		values[i] = fta.DType(i + 1)
+	}
+	return series.MakeData(1, index, values)
+}
+
+func TestIndicatorsMatchBatch(t *testing.T) {
+	const period = 14
+
+	inputs := map[string]series.Data{
+		"gbm":    fta.Generate(fta.GBM, fta.GenerateParams{}, 500, 1).Close,
+		"rising": rising(50),
+	}
+
+	for name, close := range inputs {
+		t.Run(name, func(t *testing.T) {
+			values := close.Values()
+
+			assertMatches(t, "SMA", NewSMA(period), values, fta.SMA(close, period))
+			assertMatches(t, "EMA", NewEMA(period), values, fta.EMA(close, period, false))
+			assertMatches(t, "SSMA", NewSSMA(period), values, fta.SSMA(close, period, false))
+			assertMatches(t, "RSI", NewRSI(period), values, fta.RSI(close, period, false))
+		})
+	}
+}
+
+func TestRSIRising(t *testing.T) {
+	rsi := NewRSI(3)
+
+	if v := rsi.Update(1); !series.IsNA(v) {
+		t.Fatalf("first value is %v, want NaN", v)
+	}
+
+	for i := 2; i <= 10; i++ {
+		if v := rsi.Update(fta.DType(i)); v != 100 {
+			t.Fatalf("at %d got %v, want 100", i, v)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package stream
+
+import (
+	"encoding/binary"
+	"errors"
+	stdmath "math"
+
+	"github.com/WinPooh32/fta"
+)
+
+// snapshotVersion is written as the first byte of snapshots.
+// It must be incremented when layout of any snapshot is changed.
+const snapshotVersion = 1
+
+// ErrSnapshot is returned when the snapshot is corrupted or has unsupported version.
+var ErrSnapshot = errors.New("stream: invalid snapshot")
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (s *SMA) MarshalBinary() ([]byte, error) {
+	e := newEncoder()
+	e.int(int64(s.period))
+	e.int(int64(s.pos))
+	e.int(int64(s.count))
+	e.float(s.sum)
+	for _, v := range s.window {
+		e.float(v)
+	}
+	return e.buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+func (s *SMA) UnmarshalBinary(data []byte) error {
+	d := newDecoder(data)
+
+	var (
+		period = int(d.int())
+		pos    = int(d.int())
+		count  = int(d.int())
+		sum    = d.float()
+	)
+
+	if d.err != nil || period <= 0 || pos < 0 || pos >= period || count < 0 || count > period || len(d.buf) != period*8 {
+		return ErrSnapshot
+	}
+
+	window := make([]fta.DType, period)
+	for i := range window {
+		window[i] = d.float()
+	}
+
+	*s = SMA{period: period, window: window, pos: pos, count: count, sum: sum}
+
+	return d.finish()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (e *EMA) MarshalBinary() ([]byte, error) {
+	enc := newEncoder()
+	enc.float(e.alpha)
+	enc.float(e.value)
+	enc.bool(e.ready)
+	return enc.buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+func (e *EMA) UnmarshalBinary(data []byte) error {
+	d := newDecoder(data)
+
+	restored := EMA{
+		alpha: d.float(),
+		value: d.float(),
+		ready: d.bool(),
+	}
+
+	if err := d.finish(); err != nil {
+		return err
+	}
+
+	*e = restored
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (r *RSI) MarshalBinary() ([]byte, error) {
+	e := newEncoder()
+	e.float(r.gain.alpha)
+	e.float(r.gain.value)
+	e.bool(r.gain.ready)
+	e.float(r.loss.alpha)
+	e.float(r.loss.value)
+	e.bool(r.loss.ready)
+	e.float(r.prev)
+	e.bool(r.ready)
+	return e.buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+func (r *RSI) UnmarshalBinary(data []byte) error {
+	d := newDecoder(data)
+
+	restored := RSI{
+		gain:  EMA{alpha: d.float(), value: d.float(), ready: d.bool()},
+		loss:  EMA{alpha: d.float(), value: d.float(), ready: d.bool()},
+		prev:  d.float(),
+		ready: d.bool(),
+	}
+
+	if err := d.finish(); err != nil {
+		return err
+	}
+
+	*r = restored
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (b *BarBuilder) MarshalBinary() ([]byte, error) {
+	e := newEncoder()
+	e.int(b.freq)
+	e.bool(b.open)
+	e.int(b.bar.Time)
+	e.float(b.bar.Open)
+	e.float(b.bar.High)
+	e.float(b.bar.Low)
+	e.float(b.bar.Close)
+	e.float(b.bar.Volume)
+	return e.buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+func (b *BarBuilder) UnmarshalBinary(data []byte) error {
+	d := newDecoder(data)
+
+	restored := BarBuilder{
+		freq: d.int(),
+		open: d.bool(),
+		bar: fta.Bar{
+			Time:   d.int(),
+			Open:   d.float(),
+			High:   d.float(),
+			Low:    d.float(),
+			Close:  d.float(),
+			Volume: d.float(),
+		},
+	}
+
+	if err := d.finish(); err != nil {
+		return err
+	}
+
+	if restored.freq <= 0 {
+		return ErrSnapshot
+	}
+
+	*b = restored
+
+	return nil
+}
+
+type encoder struct {
+	buf []byte
+}
+
+func newEncoder() *encoder {
+	return &encoder{buf: []byte{snapshotVersion}}
+}
+
+func (e *encoder) int(v int64) {
+	e.uint(uint64(v))
+}
+
+// float always writes float64, so snapshots are portable between float32 and float64 builds.
+func (e *encoder) float(v fta.DType) {
+	e.uint(stdmath.Float64bits(float64(v)))
+}
+
+func (e *encoder) uint(v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func newDecoder(data []byte) *decoder {
+	if len(data) == 0 || data[0] != snapshotVersion {
+		return &decoder{err: ErrSnapshot}
+	}
+	return &decoder{buf: data[1:]}
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil || len(d.buf) < n {
+		d.err = ErrSnapshot
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) int() int64 {
+	if b := d.next(8); b != nil {
+		return int64(binary.LittleEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (d *decoder) float() fta.DType {
+	if b := d.next(8); b != nil {
+		return fta.DType(stdmath.Float64frombits(binary.LittleEndian.Uint64(b)))
+	}
+	return 0
+}
+
+func (d *decoder) bool() bool {
+	if b := d.next(1); b != nil {
+		return b[0] != 0
+	}
+	return false
+}
+
+// finish returns error if decoding failed or the data has trailing bytes.
+func (d *decoder) finish() error {
+	if d.err != nil || len(d.buf) != 0 {
+		return ErrSnapshot
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package stream
+
+import (
+	"encoding"
+	"errors"
+	"testing"
+
+	"github.com/WinPooh32/fta"
+)
+
+type snapshotter interface {
+	updater
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	const period = 10
+
+	values := fta.Generate(fta.GBM, fta.GenerateParams{}, 100, 2).Close.Values()
+
+	tests := []struct {
+		name     string
+		new      func() snapshotter
+		restored snapshotter
+	}{
+		{"SMA", func() snapshotter { return NewSMA(period) }, &SMA{}},
+		{"EMA", func() snapshotter { return NewEMA(period) }, &EMA{}},
+		{"RSI", func() snapshotter { return NewRSI(period) }, &RSI{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				live  = tt.new()
+				whole = tt.new()
+			)
+
+			for _, v := range values[:50] {
+				live.Update(v)
+				whole.Update(v)
+			}
+
+			data, err := live.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.restored.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+
+			// Restored state continues exactly like uninterrupted one.
+			for i, v := range values[50:] {
+				got, want := tt.restored.Update(v), whole.Update(v)
+				if got != want && !(got != got && want != want) {
+					t.Fatalf("at %d got %v, want %v", 50+i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBarBuilderSnapshot(t *testing.T) {
+	b := NewBarBuilder(60)
+	b.Add(Trade{Time: 61, Price: 10, Size: 1})
+	b.Add(Trade{Time: 70, Price: 12, Size: 2})
+
+	data, err := b.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var restored BarBuilder
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	bar, closed := restored.Add(Trade{Time: 125, Price: 11, Size: 1})
+	want := fta.Bar{Time: 60, Open: 10, High: 12, Low: 10, Close: 12, Volume: 3}
+
+	if !closed || bar != want {
+		t.Fatalf("got %+v closed %v, want %+v", bar, closed, want)
+	}
+}
+
+func TestSnapshotCorrupted(t *testing.T) {
+	data, err := NewSMA(3).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]byte{
+		"empty":     nil,
+		"version":   append([]byte{snapshotVersion + 1}, data[1:]...),
+		"truncated": data[:len(data)-1],
+		"trailing":  append(append([]byte(nil), data...), 0),
+	}
+
+	for name, corrupted := range tests {
+		t.Run(name, func(t *testing.T) {
+			var s SMA
+			if err := s.UnmarshalBinary(corrupted); !errors.Is(err, ErrSnapshot) {
+				t.Fatalf("got %v, want ErrSnapshot", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Package stream feeds ohlcv bars from message brokers to live consumers.
+//
+// The package has no dependencies on broker clients, they are plugged in by the Source interface.
+// Kafka reader of github.com/segmentio/kafka-go:
+//
+//	src := stream.SourceFunc(func(ctx context.Context) ([]byte, error) {
+//		msg, err := reader.ReadMessage(ctx)
+//		return msg.Value, err
+//	})
+//
+// NATS subscription of github.com/nats-io/nats.go:
+//
+//	src := stream.SourceFunc(func(ctx context.Context) ([]byte, error) {
+//		msg, err := sub.NextMsgWithContext(ctx)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return msg.Data, nil
+//	})
+//
+// Stateful types of the package implement encoding.BinaryMarshaler and encoding.BinaryUnmarshaler,
+// so a live process can persist its state and resume after restart without replaying history.
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/WinPooh32/fta"
+)
+
+// Source returns payloads of messages one by one.
+// Next blocks until the message is received. io.EOF means the end of stream.
+type Source interface {
+	Next(ctx context.Context) ([]byte, error)
+}
+
+// SourceFunc is an adapter of functions to Source interface.
+type SourceFunc func(ctx context.Context) ([]byte, error)
+
+// Next implements Source interface.
+func (f SourceFunc) Next(ctx context.Context) ([]byte, error) {
+	return f(ctx)
+}
+
+// ChanSource is a Source reading payloads from the channel. Closed channel means the end of stream.
+type ChanSource <-chan []byte
+
+// Next implements Source interface.
+func (ch ChanSource) Next(ctx context.Context) ([]byte, error) {
+	select {
+	case data, ok := <-ch:
+		if !ok {
+			return nil, io.EOF
+		}
+		return data, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Trade is a single deal.
+type Trade struct {
+	Time        int64
+	Price, Size fta.DType
+}
+
+// Message is a decoded message which holds either a bar or a trade.
+type Message struct {
+	Bar     fta.Bar
+	Trade   Trade
+	IsTrade bool
+}
+
+// Decoder decodes payloads of messages.
+type Decoder interface {
+	Decode(data []byte) (Message, error)
+}
+
+// DecoderFunc is an adapter of functions to Decoder interface.
+type DecoderFunc func(data []byte) (Message, error)
+
+// Decode implements Decoder interface.
+func (f DecoderFunc) Decode(data []byte) (Message, error) {
+	return f(data)
+}
+
+// Consumer reads messages from the source and passes bars to the handler.
+// Trades are aggregated to bars of Freq size by BarBuilder, the bar is passed when it's closed.
+type Consumer struct {
+	Source  Source
+	Decoder Decoder
+	// Freq is the size of bars built from trades. Required when the source produces trades.
+	Freq int64
+	// OnBar receives bars in order of arrival. Consuming stops at the first error returned by OnBar.
+	OnBar func(bar fta.Bar) error
+	// OnError handles decoding errors, nil means to stop consuming.
+	// Consuming stops at the first error returned by OnError.
+	OnError func(data []byte, err error) error
+}
+
+// Run consumes messages until the end of stream, an error or cancellation of the context.
+// The last unfinished bar built from trades is passed to OnBar at the end of stream.
+func (c Consumer) Run(ctx context.Context) error {
+	var builder *BarBuilder
+
+	for {
+		data, err := c.Source.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("stream: next: %w", err)
+		}
+
+		msg, err := c.Decoder.Decode(data)
+		if err != nil {
+			if c.OnError == nil {
+				return fmt.Errorf("stream: decode: %w", err)
+			}
+			if err := c.OnError(data, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !msg.IsTrade {
+			if err := c.OnBar(msg.Bar); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if builder == nil {
+			if c.Freq <= 0 {
+				return errors.New("stream: freq is required to build bars from trades")
+			}
+			builder = NewBarBuilder(c.Freq)
+		}
+
+		if bar, closed := builder.Add(msg.Trade); closed {
+			if err := c.OnBar(bar); err != nil {
+				return err
+			}
+		}
+	}
+
+	if builder != nil {
+		if bar, ok := builder.Flush(); ok {
+			return c.OnBar(bar)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package fta
+
+import (
+	stdmath "math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// tpoLetters are letters of brackets, they repeat after the 52nd bracket.
+const tpoLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// MarketProfileParams are parameters of market profiles.
+type MarketProfileParams struct {
+	// TickSize is the price height of a row, default is 1.
+	TickSize float64
+	// Bracket is the time of a single TPO letter, default is 30 minutes.
+	Bracket time.Duration
+	// Session is the length of sessions aligned to the unix epoch shifted by Offset, default is 24 hours.
+	Session time.Duration
+	Offset  time.Duration
+	// InitialBalance is the number of first brackets forming the initial balance, default is 2.
+	InitialBalance int
+}
+
+func (p MarketProfileParams) withDefaults() MarketProfileParams {
+	if p.TickSize <= 0 {
+		p.TickSize = 1
+	}
+	if p.Bracket <= 0 {
+		p.Bracket = 30 * time.Minute
+	}
+	if p.Session <= 0 {
+		p.Session = 24 * time.Hour
+	}
+	if p.InitialBalance <= 0 {
+		p.InitialBalance = 2
+	}
+	return p
+}
+
+// MarketProfile is the time price opportunity (TPO) profile of a session: every price row holds letters
+// of brackets that traded at the row, A is the first bracket of the session.
+type MarketProfile struct {
+	// Start is the start of the session.
+	Start int64
+	// Low is the price of the first row, TickSize is the price step between rows.
+	Low, TickSize DType
+	// TPO are letters of every row starting from Low.
+	TPO []string
+	// POC is the point of control: the price of the row with the most TPOs closest to the middle of the range.
+	POC DType
+	// VAH and VAL are prices of the highest and the lowest row of the value area holding ValueAreaFraction of TPOs.
+	VAH, VAL DType
+	// IBHigh and IBLow are the range of the initial balance.
+	IBHigh, IBLow DType
+}
+
+// Price returns the price of i-th row.
+func (mp MarketProfile) Price(i int) DType {
+	return mp.Low + DType(i)*mp.TickSize
+}
+
+// String renders rows from the highest price to the lowest, the point of control is marked by an asterisk.
+func (mp MarketProfile) String() string {
+	var (
+		b       strings.Builder
+		bitSize = 64
+	)
+
+	if series.EnabledFloat32 {
+		bitSize = 32
+	}
+
+	for i := len(mp.TPO) - 1; i >= 0; i-- {
+		price := mp.Price(i)
+
+		mark := " "
+		if price == mp.POC {
+			mark = "*"
+		}
+
+		b.WriteString(strconv.FormatFloat(float64(price), 'f', -1, bitSize))
+		b.WriteString(" ")
+		b.WriteString(mark)
+		b.WriteString(" ")
+		b.WriteString(mp.TPO[i])
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// MarketProfiles returns TPO profiles of sessions, sessions without bars are skipped.
+// Bars should be shorter than brackets, e.g. 30 minute brackets are built from 1 or 5 minute bars.
+// Levels of profiles are NaN when sessions have no valid bars.
+func MarketProfiles(ohlcv OHLCV, params MarketProfileParams) (profiles []MarketProfile) {
+	params = params.withDefaults()
+
+	var (
+		h     = ohlcv.High.Values()
+		l     = ohlcv.Low.Values()
+		index = ohlcv.Close.Index()
+
+		session = int64(params.Session)
+		offset  = int64(params.Offset)
+		begin   int
+	)
+
+	for i := range index {
+		if i == len(index)-1 || sessionStart(index[i+1], session, offset) != sessionStart(index[i], session, offset) {
+			start := sessionStart(index[begin], session, offset)
+			profiles = append(profiles, marketProfile(h[begin:i+1], l[begin:i+1], index[begin:i+1], start, params))
+			begin = i + 1
+		}
+	}
+
+	return profiles
+}
+
+// marketProfile builds the profile of bars of a single session.
+func marketProfile(h, l []DType, index []int64, start int64, params MarketProfileParams) MarketProfile {
+	var (
+		nan  = math.NaN()
+		tick = DType(params.TickSize)
+
+		mp = MarketProfile{
+			Start:    start,
+			Low:      nan,
+			TickSize: tick,
+			POC:      nan,
+			VAH:      nan,
+			VAL:      nan,
+			IBHigh:   nan,
+			IBLow:    nan,
+		}
+
+		row = func(price DType) int {
+			return int(stdmath.Floor(float64(price / tick)))
+		}
+
+		lowest, highest int
+		valid           bool
+	)
+
+	for i := range h {
+		if series.IsNA(h[i]) || series.IsNA(l[i]) {
+			continue
+		}
+		lo, hi := row(l[i]), row(h[i])
+		if !valid || lo < lowest {
+			lowest = lo
+		}
+		if !valid || hi > highest {
+			highest = hi
+		}
+		valid = true
+	}
+
+	if !valid {
+		return mp
+	}
+
+	var (
+		rows    = make([][]byte, highest-lowest+1)
+		counts  = make([]DType, len(rows))
+		bracket = int64(params.Bracket)
+	)
+
+	mp.Low = DType(lowest) * tick
+
+	// Brackets of bars are ascending, so letters are appended in order and every bracket is marked once per row.
+	last := make([]int, len(rows))
+	for k := range last {
+		last[k] = -1
+	}
+
+	for i := range h {
+		if series.IsNA(h[i]) || series.IsNA(l[i]) {
+			continue
+		}
+
+		n := int((index[i] - start) / bracket)
+		letter := tpoLetters[n%len(tpoLetters)]
+
+		for k := row(l[i]) - lowest; k <= row(h[i])-lowest; k++ {
+			if last[k] == n {
+				continue
+			}
+			last[k] = n
+			rows[k] = append(rows[k], letter)
+			counts[k]++
+		}
+
+		if n < params.InitialBalance {
+			if series.IsNA(mp.IBHigh) || h[i] > mp.IBHigh {
+				mp.IBHigh = h[i]
+			}
+			if series.IsNA(mp.IBLow) || l[i] < mp.IBLow {
+				mp.IBLow = l[i]
+			}
+		}
+	}
+
+	mp.TPO = make([]string, len(rows))
+	for k := range rows {
+		mp.TPO[k] = string(rows[k])
+	}
+
+	var (
+		poc    int
+		middle = DType(len(counts)-1) / 2
+	)
+
+	for k, c := range counts {
+		if c > counts[poc] || (c == counts[poc] && math.Abs(DType(k)-middle) < math.Abs(DType(poc)-middle)) {
+			poc = k
+		}
+	}
+
+	lo, hi := valueArea(counts, poc, ValueAreaFraction)
+
+	mp.POC = mp.Price(poc)
+	mp.VAL = mp.Price(lo)
+	mp.VAH = mp.Price(hi)
+
+	return mp
+}
@@ -0,0 +1,310 @@
+package tsdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/WinPooh32/fta"
+)
+
+// InfluxClient queries InfluxDB by Flux language over HTTP API v2
+// or by InfluxQL over API v1, which is also served by InfluxDB 2.x for compatibility.
+type InfluxClient struct {
+	// URL is the address of InfluxDB server, e.g. http://localhost:8086.
+	URL string
+	// Token is the API token, empty token means no authorization.
+	Token string
+	// Org is the organization name of Flux queries.
+	Org string
+	// HTTPClient is used to make requests, nil means http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// InfluxFields are names of ohlcv fields of the measurement.
+// Zero value of names means names of fta.DefaultSQLMapping.
+type InfluxFields struct{ Open, High, Low, Close, Volume string }
+
+// InfluxQuery builds Flux query aggregating ohlcv fields of the measurement into windows of the given size.
+// Bars are labeled by the start of window.
+func InfluxQuery(bucket, measurement string, fields InfluxFields, every time.Duration, from, to time.Time) string {
+	fields = fields.withDefaults()
+
+	aggregate := func(field, fn string) string {
+		return fmt.Sprintf(`data |> filter(fn: (r) => r._field == %q) |> aggregateWindow(every: %s, fn: %s, timeSrc: "_start", createEmpty: false)`,
+			field, influxDuration(every), fn)
+	}
+
+	return fmt.Sprintf(`data = from(bucket: %q)
+	|> range(start: %s, stop: %s)
+	|> filter(fn: (r) => r._measurement == %q)
+
+union(tables: [
+	%s,
+	%s,
+	%s,
+	%s,
+	%s,
+])
+	|> keep(columns: ["_time", "_field", "_value"])
+	|> group()
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> sort(columns: ["_time"])`,
+		bucket, from.UTC().Format(time.RFC3339Nano), to.UTC().Format(time.RFC3339Nano), measurement,
+		aggregate(fields.Open, "first"),
+		aggregate(fields.High, "max"),
+		aggregate(fields.Low, "min"),
+		aggregate(fields.Close, "last"),
+		aggregate(fields.Volume, "sum"),
+	)
+}
+
+// ReadOHLCV reads bars of the measurement in range [from, to) resampled by InfluxDB to the window size.
+func (c InfluxClient) ReadOHLCV(ctx context.Context, bucket, measurement string, fields InfluxFields, every time.Duration, from, to time.Time) (fta.OHLCV, error) {
+	body, err := c.Query(ctx, InfluxQuery(bucket, measurement, fields, every, from, to))
+	if err != nil {
+		return fta.OHLCV{}, err
+	}
+	defer body.Close()
+
+	ohlcv, err := readInfluxCSV(body, "_time", parseRFC3339, fields.withDefaults())
+	if err != nil {
+		return ohlcv, fmt.Errorf("influx: %w", err)
+	}
+
+	return ohlcv, nil
+}
+
+// InfluxQLQuery builds InfluxQL query aggregating ohlcv fields of the measurement into windows of the given size.
+// Bars are labeled by the start of window, windows without points are skipped.
+func InfluxQLQuery(measurement string, fields InfluxFields, every time.Duration, from, to time.Time) string {
+	fields = fields.withDefaults()
+
+	aggregate := func(field, fn string) string {
+		return fmt.Sprintf("%s(%s) AS %[2]s", fn, influxQLIdent(field))
+	}
+
+	return fmt.Sprintf(`SELECT %s, %s, %s, %s, %s FROM %s WHERE time >= '%s' AND time < '%s' GROUP BY time(%s) fill(none)`,
+		aggregate(fields.Open, "first"),
+		aggregate(fields.High, "max"),
+		aggregate(fields.Low, "min"),
+		aggregate(fields.Close, "last"),
+		aggregate(fields.Volume, "sum"),
+		influxQLIdent(measurement),
+		from.UTC().Format(time.RFC3339Nano), to.UTC().Format(time.RFC3339Nano),
+		influxDuration(every),
+	)
+}
+
+// ReadOHLCVInfluxQL reads bars of the measurement of the database in range [from, to)
+// resampled by InfluxDB to the window size using InfluxQL.
+func (c InfluxClient) ReadOHLCVInfluxQL(ctx context.Context, db, measurement string, fields InfluxFields, every time.Duration, from, to time.Time) (fta.OHLCV, error) {
+	body, err := c.QueryInfluxQL(ctx, db, InfluxQLQuery(measurement, fields, every, from, to))
+	if err != nil {
+		return fta.OHLCV{}, err
+	}
+	defer body.Close()
+
+	ohlcv, err := readInfluxCSV(body, "time", parseEpochNano, fields.withDefaults())
+	if err != nil {
+		return ohlcv, fmt.Errorf("influx: %w", err)
+	}
+
+	return ohlcv, nil
+}
+
+// QueryInfluxQL executes InfluxQL query against the database and returns response body of CSV
+// with timestamps in nanoseconds. The caller must close the body.
+func (c InfluxClient) QueryInfluxQL(ctx context.Context, db, query string) (io.ReadCloser, error) {
+	form := url.Values{
+		"db":    {db},
+		"q":     {query},
+		"epoch": {"ns"},
+	}
+
+	endpoint := strings.TrimRight(c.URL, "/") + "/query"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("influx: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/csv")
+
+	return c.do(req)
+}
+
+// Query executes Flux query and returns response body of CSV without annotations.
+// The caller must close the body.
+func (c InfluxClient) Query(ctx context.Context, flux string) (io.ReadCloser, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"query": flux,
+		"type":  "flux",
+		"dialect": map[string]interface{}{
+			"header":      true,
+			"annotations": []string{},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("influx: marshal query: %w", err)
+	}
+
+	endpoint := strings.TrimRight(c.URL, "/") + "/api/v2/query?org=" + url.QueryEscape(c.Org)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("influx: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/csv")
+
+	return c.do(req)
+}
+
+// do sends the authorized request and returns the body of successful response.
+func (c InfluxClient) do(req *http.Request) (io.ReadCloser, error) {
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Token "+c.Token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("influx: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("influx: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	return resp.Body, nil
+}
+
+func (fields InfluxFields) withDefaults() InfluxFields {
+	m := fta.SQLMapping{
+		Open:   fields.Open,
+		High:   fields.High,
+		Low:    fields.Low,
+		Close:  fields.Close,
+		Volume: fields.Volume,
+	}.WithDefaults()
+
+	return InfluxFields{Open: m.Open, High: m.High, Low: m.Low, Close: m.Close, Volume: m.Volume}
+}
+
+// readInfluxCSV parses pivoted query result. Tables of the result may repeat the header.
+// Empty cells of fields missing in the window are NaN.
+func readInfluxCSV(r io.Reader, timeColumn string, parseTime func(string) (time.Time, error), fields InfluxFields) (fta.OHLCV, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return fta.FromCandles(nil), nil
+	}
+	if err != nil {
+		return fta.OHLCV{}, fmt.Errorf("read csv header: %w", err)
+	}
+
+	find := func(name string) (int, error) {
+		for i, h := range header {
+			if h == name {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("column %q not found", name)
+	}
+
+	names := []string{timeColumn, fields.Open, fields.High, fields.Low, fields.Close, fields.Volume}
+	positions := make([]int, len(names))
+
+	for i, name := range names {
+		if positions[i], err = find(name); err != nil {
+			return fta.OHLCV{}, err
+		}
+	}
+
+	var candles []fta.Candle
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fta.OHLCV{}, fmt.Errorf("read csv: %w", err)
+		}
+
+		if len(record) <= positions[0] || record[positions[0]] == timeColumn {
+			// Header of the next table.
+			continue
+		}
+
+		var candle fta.Candle
+
+		candle.Time, err = parseTime(record[positions[0]])
+		if err != nil {
+			return fta.OHLCV{}, fmt.Errorf("parse time: %w", err)
+		}
+
+		values := []*float64{&candle.O, &candle.H, &candle.L, &candle.C, &candle.V}
+		for i, v := range values {
+			cell := record[positions[i+1]]
+			if cell == "" {
+				*v = math.NaN()
+				continue
+			}
+			*v, err = strconv.ParseFloat(cell, 64)
+			if err != nil {
+				return fta.OHLCV{}, fmt.Errorf("parse float: field %q: %w", names[i+1], err)
+			}
+		}
+
+		candles = append(candles, candle)
+	}
+
+	return fta.FromCandles(candles), nil
+}
+
+func parseRFC3339(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+func parseEpochNano(s string) (time.Time, error) {
+	ns, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, ns).UTC(), nil
+}
+
+// influxQLIdent quotes the identifier of InfluxQL.
+func influxQLIdent(name string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name) + `"`
+}
+
+// influxDuration formats duration as Flux and InfluxQL duration literal.
+func influxDuration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	}
+	return strconv.FormatInt(int64(d), 10) + "ns"
+}
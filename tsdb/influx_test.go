@@ -0,0 +1,132 @@
+package tsdb
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WinPooh32/fta"
+)
+
+func TestReadOHLCV(t *testing.T) {
+	const response = `,result,table,_time,close,high,low,open,volume
+,_result,0,2023-01-02T10:00:00Z,2,3,1,1.5,10
+,_result,0,2023-01-02T10:01:00Z,2.5,,2,2,
+
+,result,table,_time,close,high,low,open,volume
+,_result,1,2023-01-02T10:02:00Z,3,3.5,2.5,2.5,30
+`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/query" || r.URL.Query().Get("org") != "org" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if got := r.Header.Get("Authorization"); got != "Token secret" {
+			t.Errorf("authorization %q", got)
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	client := InfluxClient{URL: server.URL, Token: "secret", Org: "org"}
+	from := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	ohlcv, err := client.ReadOHLCV(context.Background(), "bucket", "btcusd", InfluxFields{}, time.Minute, from, from.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertOHLCV(t, ohlcv, from, [][]float64{
+		{1.5, 3, 1, 2, 10},
+		{2, math.NaN(), 2, 2.5, math.NaN()},
+		{2.5, 3.5, 2.5, 3, 30},
+	})
+}
+
+func TestReadOHLCVInfluxQL(t *testing.T) {
+	from := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/query" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("authorization %q without token", got)
+		}
+		if r.FormValue("db") != "market" || r.FormValue("epoch") != "ns" {
+			t.Errorf("unexpected form %v", r.Form)
+		}
+		if q := r.FormValue("q"); !strings.Contains(q, `FROM "btcusd"`) {
+			t.Errorf("unexpected query %s", q)
+		}
+
+		_, _ = w.Write([]byte("name,tags,time,open,high,low,close,volume\n" +
+			"btcusd,,1672653600000000000,1.5,3,1,2,10\n" +
+			"btcusd,,1672653660000000000,2,,2,2.5,\n"))
+	}))
+	defer server.Close()
+
+	client := InfluxClient{URL: server.URL}
+
+	ohlcv, err := client.ReadOHLCVInfluxQL(context.Background(), "market", "btcusd", InfluxFields{}, time.Minute, from, from.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertOHLCV(t, ohlcv, from, [][]float64{
+		{1.5, 3, 1, 2, 10},
+		{2, math.NaN(), 2, 2.5, math.NaN()},
+	})
+}
+
+func TestInfluxQLQuery(t *testing.T) {
+	from := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	got := InfluxQLQuery(`my "bars"`, InfluxFields{Close: "price"}, 90*time.Second, from, from.Add(time.Hour))
+	want := `SELECT first("open") AS "open", max("high") AS "high", min("low") AS "low", last("price") AS "price", sum("volume") AS "volume" ` +
+		`FROM "my \"bars\"" WHERE time >= '2023-01-02T10:00:00Z' AND time < '2023-01-02T11:00:00Z' GROUP BY time(90s) fill(none)`
+
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestQueryStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "database not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := InfluxClient{URL: server.URL}.QueryInfluxQL(context.Background(), "market", "SELECT 1")
+	if err == nil || !strings.Contains(err.Error(), "database not found") {
+		t.Fatalf("got error %v", err)
+	}
+}
+
+// assertOHLCV compares bars of one minute frequency starting from the time, NaN values are equal.
+func assertOHLCV(t *testing.T, ohlcv fta.OHLCV, from time.Time, want [][]float64) {
+	t.Helper()
+
+	if ohlcv.Len() != len(want) {
+		t.Fatalf("got %d bars, want %d", ohlcv.Len(), len(want))
+	}
+
+	for i, w := range want {
+		bar := ohlcv.Bar(i)
+		if tm := from.Add(time.Duration(i) * time.Minute); bar.Time != tm.UnixNano() {
+			t.Errorf("bar %d: time %d, want %d", i, bar.Time, tm.UnixNano())
+		}
+
+		got := []float64{float64(bar.Open), float64(bar.High), float64(bar.Low), float64(bar.Close), float64(bar.Volume)}
+		for k := range w {
+			if got[k] != w[k] && !(math.IsNaN(got[k]) && math.IsNaN(w[k])) {
+				t.Errorf("bar %d: got %v, want %v", i, got, w)
+				break
+			}
+		}
+	}
+}
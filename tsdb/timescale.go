@@ -0,0 +1,44 @@
+// Package tsdb provides loaders of ohlcv frames from time-series databases.
+// Resampling is pushed down to the database, so only aggregated bars are transferred.
+package tsdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/WinPooh32/fta"
+)
+
+// TimescaleQuery builds TimescaleDB query aggregating bars of the hypertable into buckets of the given size.
+// Result columns are named by mapping, so the query can be read by fta.ReadSQL.
+// Query parameters are bucket size in seconds, begin and end of the time range, end is exclusive.
+func TimescaleQuery(table string, mapping fta.SQLMapping) string {
+	m := mapping.WithDefaults()
+
+	return fmt.Sprintf(`SELECT
+	time_bucket(make_interval(secs => $1), %[2]s) AS %[2]s,
+	first(%[3]s, %[2]s) AS %[3]s,
+	max(%[4]s) AS %[4]s,
+	min(%[5]s) AS %[5]s,
+	last(%[6]s, %[2]s) AS %[6]s,
+	sum(%[7]s) AS %[7]s
+FROM %[1]s
+WHERE %[2]s >= $2 AND %[2]s < $3
+GROUP BY 1
+ORDER BY 1`,
+		table, m.Time, m.Open, m.High, m.Low, m.Close, m.Volume)
+}
+
+// ReadTimescale reads bars of the hypertable in range [from, to) resampled by TimescaleDB to the bucket size.
+// The time column of the table must be of timestamp type.
+func ReadTimescale(ctx context.Context, db fta.SQLQueryer, table string, mapping fta.SQLMapping, bucket time.Duration, from, to time.Time) (fta.OHLCV, error) {
+	query := TimescaleQuery(table, mapping)
+
+	ohlcv, err := fta.ReadSQL(ctx, db, query, mapping, bucket.Seconds(), from, to)
+	if err != nil {
+		return ohlcv, fmt.Errorf("timescale: %w", err)
+	}
+
+	return ohlcv, nil
+}
@@ -0,0 +1,153 @@
+package fta
+
+import (
+	stdmath "math"
+
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// HV is the close-to-close historical volatility: the sample standard deviation of log returns over the period
+// scaled by the square root of annualization, the number of bars per year, e.g. 252 for daily bars of stocks
+// and 365 for crypto. Annualization 1 keeps volatility per bar. The first period values are NaN.
+func HV(close series.Data, period int, annualization float64) (hv series.Data) {
+	c := close.Values()
+
+	returns := make([]float64, len(c))
+	for i := 1; i < len(c); i++ {
+		returns[i] = stdmath.Log(float64(c[i] / c[i-1]))
+	}
+
+	hv = close.Clone()
+	values := hv.Values()
+
+	for i := range values {
+		values[i] = math.NaN()
+
+		if i < period || period < 2 {
+			continue
+		}
+
+		var (
+			window   = returns[i-period+1 : i+1]
+			mean, ss float64
+		)
+
+		for _, r := range window {
+			mean += r
+		}
+		mean /= float64(period)
+
+		for _, r := range window {
+			ss += (r - mean) * (r - mean)
+		}
+
+		if !stdmath.IsNaN(ss) {
+			values[i] = DType(stdmath.Sqrt(ss / float64(period-1) * annualization))
+		}
+	}
+
+	return hv
+}
+
+// ParkinsonHV is Parkinson's volatility estimated from high-low ranges. It's about 5 times more efficient than
+// close-to-close volatility but ignores gaps and drift. Annualization is the number of bars per year.
+// The first period-1 values are NaN.
+func ParkinsonHV(high, low series.Data, period int, annualization float64) (hv series.Data) {
+	var (
+		h = high.Values()
+		l = low.Values()
+		k = 1 / (4 * stdmath.Ln2)
+	)
+
+	return rangeVolatility(high, period, annualization, func(i int) float64 {
+		hl := stdmath.Log(float64(h[i] / l[i]))
+		return k * hl * hl
+	})
+}
+
+// GarmanKlassHV is the Garman-Klass volatility estimated from open, high, low and close of bars.
+// It's more efficient than Parkinson's estimator but assumes zero drift and no gaps between bars.
+// Annualization is the number of bars per year. The first period-1 values are NaN.
+func GarmanKlassHV(open, high, low, close series.Data, period int, annualization float64) (hv series.Data) {
+	var (
+		o = open.Values()
+		h = high.Values()
+		l = low.Values()
+		c = close.Values()
+		k = 2*stdmath.Ln2 - 1
+	)
+
+	return rangeVolatility(close, period, annualization, func(i int) float64 {
+		hl := stdmath.Log(float64(h[i] / l[i]))
+		co := stdmath.Log(float64(c[i] / o[i]))
+		return 0.5*hl*hl - k*co*co
+	})
+}
+
+// RogersSatchellHV is the Rogers-Satchell volatility estimated from open, high, low and close of bars.
+// Unlike Parkinson and Garman-Klass estimators it's unbiased under non-zero drift.
+// Annualization is the number of bars per year. The first period-1 values are NaN.
+func RogersSatchellHV(open, high, low, close series.Data, period int, annualization float64) (hv series.Data) {
+	var (
+		o = open.Values()
+		h = high.Values()
+		l = low.Values()
+		c = close.Values()
+	)
+
+	return rangeVolatility(close, period, annualization, func(i int) float64 {
+		hc := stdmath.Log(float64(h[i] / c[i]))
+		ho := stdmath.Log(float64(h[i] / o[i]))
+		lc := stdmath.Log(float64(l[i] / c[i]))
+		lo := stdmath.Log(float64(l[i] / o[i]))
+		return hc*ho + lc*lo
+	})
+}
+
+// rangeVolatility returns the square root of the mean of per bar variances over the period times annualization.
+func rangeVolatility(like series.Data, period int, annualization float64, variance func(i int) float64) series.Data {
+	hv := like.Clone()
+	values := hv.Values()
+
+	terms := make([]float64, len(values))
+	for i := range terms {
+		terms[i] = variance(i)
+	}
+
+	for i := range values {
+		values[i] = math.NaN()
+
+		if i < period-1 {
+			continue
+		}
+
+		var sum float64
+		for _, v := range terms[i-period+1 : i+1] {
+			sum += v
+		}
+
+		if !stdmath.IsNaN(sum) {
+			values[i] = DType(stdmath.Sqrt(stdmath.Max(sum/float64(period), 0) * annualization))
+		}
+	}
+
+	return hv
+}
+
+// UI is the ulcer index of Peter Martin: the root mean square of percentage drawdowns of closes from the highest close
+// of the period, over the period. It measures depth and duration of drawdowns, unlike the standard deviation
+// it doesn't penalize upside moves. The first 2*period-2 values are NaN.
+func UI(close series.Data, period int) (ui series.Data) {
+	highest := RollingMax(close, period)
+	drawdown := div(close.Clone().Sub(highest), highest).MulScalar(100)
+
+	ui = nanHead(SMA(drawdown.Mul(drawdown), period), 2*period-2)
+
+	values := ui.Values()
+	for i, v := range values {
+		values[i] = math.Sqrt(v)
+	}
+
+	return ui
+}
@@ -0,0 +1,15 @@
+//go:build js && wasm
+// +build js,wasm
+
+// Command fta is the WebAssembly module which registers global JavaScript function fta.
+// See package wasm for the format of requests.
+package main
+
+import "github.com/WinPooh32/fta/wasm"
+
+func main() {
+	wasm.Register("fta")
+
+	// Keep the module alive to serve calls from JavaScript.
+	select {}
+}
@@ -0,0 +1,17 @@
+//go:build js && wasm
+// +build js,wasm
+
+package wasm
+
+import "syscall/js"
+
+// Register sets global JavaScript function of the name which calls Compute.
+// The function takes JSON string of Request and returns JSON string of Response.
+func Register(name string) {
+	js.Global().Set(name, js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) != 1 || args[0].Type() != js.TypeString {
+			return `{"error":"expected single JSON string argument"}`
+		}
+		return string(Compute([]byte(args[0].String())))
+	}))
+}
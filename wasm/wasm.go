@@ -0,0 +1,289 @@
+// Package wasm exposes indicators of fta package over JSON, so they can be called from JavaScript
+// when the program is compiled to WebAssembly:
+//
+//	GOOS=js GOARCH=wasm go build -o fta.wasm ./wasm/cmd/fta
+//
+// The module registers global function fta(request) which takes and returns JSON strings:
+//
+//	const response = JSON.parse(fta(JSON.stringify({
+//		indicator: "RSI",
+//		params: {period: 14},
+//		ohlcv: {t: [...], o: [...], h: [...], l: [...], c: [...], v: [...]},
+//	})));
+//
+// Timestamps are milliseconds since epoch by default, NaN values are encoded as null.
+// Response is the object of indicator outputs, each encoded like fta.SeriesJSON:
+//
+//	{"outputs": {"rsi": {"t": [...], "v": [...]}}}
+//
+// or {"error": "..."} on failure.
+//
+// Compute is available on every platform, so the same requests can be served by Go backend.
+package wasm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/WinPooh32/fta"
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// Request is the indicator computation request.
+type Request struct {
+	// Indicator is the name of indicator, see Indicators.
+	Indicator string `json:"indicator"`
+	// Params are named numeric or boolean parameters of indicator, missing parameters have the usual default values.
+	Params map[string]interface{} `json:"params"`
+	// Unit is the unit of timestamps: "s", "ms", "us" or "ns". Default is "ms".
+	Unit string `json:"unit"`
+	// OHLCV is the input data, columns which aren't used by indicator can be omitted.
+	OHLCV Columns `json:"ohlcv"`
+}
+
+// Columns are columns of OHLCV data of the same length.
+type Columns struct {
+	Time   []int64      `json:"t"`
+	Open   []*fta.DType `json:"o"`
+	High   []*fta.DType `json:"h"`
+	Low    []*fta.DType `json:"l"`
+	Close  []*fta.DType `json:"c"`
+	Volume []*fta.DType `json:"v"`
+}
+
+// Response is the result of computation.
+type Response struct {
+	Outputs map[string]fta.SeriesJSON `json:"outputs,omitempty"`
+	Error   string                    `json:"error,omitempty"`
+}
+
+// indicator computes outputs of indicator using params p.
+type indicator func(ohlcv fta.OHLCV, p params) map[string]series.Data
+
+var indicators = map[string]indicator{
+	"SMA": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("sma", fta.SMA(ohlcv.Close, p.int("period", 14)))
+	},
+	"SMM": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("smm", fta.SMM(ohlcv.Close, p.int("period", 9)))
+	},
+	"SSMA": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("ssma", fta.SSMA(ohlcv.Close, p.int("period", 9), p.bool("adjust")))
+	},
+	"EMA": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("ema", fta.EMA(ohlcv.Close, p.int("period", 9), p.bool("adjust")))
+	},
+	"WMA": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("wma", fta.WMA(ohlcv.Close, p.int("period", 9)))
+	},
+	"HMA": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("hma", fta.HMA(ohlcv.Close, p.int("period", 16)))
+	},
+	"ROC": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("roc", fta.ROC(ohlcv.Close.Clone(), p.int("period", 12)))
+	},
+	"MACD": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		macd, signal := fta.MACD(ohlcv.Close, p.float("fast", 12), p.float("slow", 26), p.float("signal", 9), p.bool("adjust"))
+		return map[string]series.Data{"macd": macd, "signal": signal}
+	},
+	"BBANDS": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		period := p.int("period", 20)
+		ma := fta.SMA(ohlcv.Close, period)
+		upper, middle, lower, width := fta.BBANDSFull(ohlcv.Close, ma, period, p.float("multiplier", 2))
+		return map[string]series.Data{"upper": upper, "middle": middle, "lower": lower, "width": width}
+	},
+	"PercentB": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		period := p.int("period", 20)
+		return out("percent_b", fta.PercentB(ohlcv.Close, fta.SMA(ohlcv.Close, period), period, p.float("multiplier", 2)))
+	},
+	"RSI": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("rsi", fta.RSI(ohlcv.Close, p.int("period", 14), p.bool("adjust")))
+	},
+	"RSIWilder": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("rsi", fta.RSIWilder(ohlcv.Close, p.int("period", 14)))
+	},
+	"STOCH": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("stoch", fta.STOCH(ohlcv.High, ohlcv.Low, ohlcv.Close, p.int("period", 14)))
+	},
+	"STOCHD": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("stochd", fta.STOCHD(ohlcv.High, ohlcv.Low, ohlcv.Close, p.int("period", 14)))
+	},
+	"ADL": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("adl", fta.ADL(ohlcv.High, ohlcv.Low, ohlcv.Close))
+	},
+	"VZO": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		return out("vzo", fta.VZO(ohlcv.Close, ohlcv.Volume, p.int("period", 14), p.bool("adjust")))
+	},
+	"PSAR": func(ohlcv fta.OHLCV, p params) map[string]series.Data {
+		psar, bull, bear := fta.PSAR(ohlcv.High, ohlcv.Low, ohlcv.Close, p.float("iaf", 0.02), p.float("maxaf", 0.2))
+		return map[string]series.Data{"psar": psar, "bull": bull, "bear": bear}
+	},
+}
+
+// Indicators returns sorted names of supported indicators.
+func Indicators() []string {
+	names := make([]string, 0, len(indicators))
+	for name := range indicators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Compute decodes JSON request, computes the indicator and returns JSON encoded Response.
+// Errors are reported in the response, so the result is always valid JSON.
+func Compute(request []byte) []byte {
+	var resp Response
+
+	outputs, err := compute(request)
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Outputs = outputs
+	}
+
+	b, err := json.Marshal(resp)
+	if err != nil {
+		b, _ = json.Marshal(Response{Error: err.Error()})
+	}
+
+	return b
+}
+
+func compute(request []byte) (outputs map[string]fta.SeriesJSON, err error) {
+	var req Request
+
+	if err := json.Unmarshal(request, &req); err != nil {
+		return nil, fmt.Errorf("decode request: %w", err)
+	}
+
+	fn, ok := indicators[req.Indicator]
+	if !ok {
+		return nil, fmt.Errorf("unknown indicator %q, supported: %s", req.Indicator, strings.Join(Indicators(), ", "))
+	}
+
+	unit, mul, err := parseUnit(req.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	ohlcv, err := req.OHLCV.build(mul)
+	if err != nil {
+		return nil, err
+	}
+
+	// Indicators panic on invalid parameters, e.g. zero period.
+	defer func() {
+		if r := recover(); r != nil {
+			outputs, err = nil, fmt.Errorf("%s: %v", req.Indicator, r)
+		}
+	}()
+
+	outputs = make(map[string]fta.SeriesJSON)
+
+	for name, data := range fn(ohlcv, params(req.Params)) {
+		outputs[name] = fta.SeriesJSON{Data: data, Unit: unit}
+	}
+
+	return outputs, nil
+}
+
+func parseUnit(unit string) (fta.UnixTime, int64, error) {
+	switch unit {
+	case "s":
+		return fta.Seconds, 1e9, nil
+	case "", "ms":
+		return fta.Milliseconds, 1e6, nil
+	case "us":
+		return fta.Microseconds, 1e3, nil
+	case "ns":
+		return fta.Nanoseconds, 1, nil
+	default:
+		return 0, 0, fmt.Errorf("unsupported time unit %q", unit)
+	}
+}
+
+// build makes OHLCV from columns, missing columns are filled by NaN values.
+func (c Columns) build(mul int64) (ohlcv fta.OHLCV, err error) {
+	n := len(c.Time)
+	if n == 0 {
+		return ohlcv, errors.New("ohlcv: empty time column")
+	}
+
+	column := func(name string, values []*fta.DType) (series.Data, error) {
+		if values != nil && len(values) != n {
+			return series.Data{}, fmt.Errorf("ohlcv: column %q has %d values, want %d", name, len(values), n)
+		}
+
+		var (
+			index = make([]int64, n)
+			data  = make([]fta.DType, n)
+		)
+
+		for i, t := range c.Time {
+			index[i] = t * mul
+
+			if values == nil || values[i] == nil {
+				data[i] = math.NaN()
+			} else {
+				data[i] = *values[i]
+			}
+		}
+
+		return series.MakeData(0, index, data), nil
+	}
+
+	if ohlcv.Open, err = column("o", c.Open); err != nil {
+		return ohlcv, err
+	}
+	if ohlcv.High, err = column("h", c.High); err != nil {
+		return ohlcv, err
+	}
+	if ohlcv.Low, err = column("l", c.Low); err != nil {
+		return ohlcv, err
+	}
+	if ohlcv.Close, err = column("c", c.Close); err != nil {
+		return ohlcv, err
+	}
+	if ohlcv.Volume, err = column("v", c.Volume); err != nil {
+		return ohlcv, err
+	}
+
+	return ohlcv, nil
+}
+
+// params are named parameters of request, values of wrong types are ignored.
+type params map[string]interface{}
+
+func (p params) float(name string, def float64) float64 {
+	if v, ok := p[name].(float64); ok {
+		return v
+	}
+	return def
+}
+
+func (p params) int(name string, def int) int {
+	if v, ok := p[name].(float64); ok {
+		return int(v)
+	}
+	return def
+}
+
+func (p params) bool(name string) bool {
+	switch v := p[name].(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	default:
+		return false
+	}
+}
+
+func out(name string, data series.Data) map[string]series.Data {
+	return map[string]series.Data{name: data}
+}
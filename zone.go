@@ -0,0 +1,104 @@
+package fta
+
+import (
+	"github.com/WinPooh32/series"
+	"github.com/WinPooh32/series/math"
+)
+
+// ZoneStats is the total time an oscillator spent in zones.
+type ZoneStats struct {
+	// Bars is the number of bars with defined values.
+	Bars int
+	// Above and Below are the numbers of bars above the upper and below the lower threshold.
+	Above, Below int
+	// AboveSpells and BelowSpells are the numbers of continuous stays in the zones.
+	AboveSpells, BelowSpells int
+	// LongestAbove and LongestBelow are the lengths of the longest stays in the zones.
+	LongestAbove, LongestBelow int
+}
+
+// AboveRatio returns the fraction of bars above the upper threshold.
+func (s ZoneStats) AboveRatio() DType {
+	return zoneRatio(s.Above, s.Bars)
+}
+
+// BelowRatio returns the fraction of bars below the lower threshold.
+func (s ZoneStats) BelowRatio() DType {
+	return zoneRatio(s.Below, s.Bars)
+}
+
+// MeanAbove returns the mean length of stays above the upper threshold.
+func (s ZoneStats) MeanAbove() DType {
+	return zoneRatio(s.Above, s.AboveSpells)
+}
+
+// MeanBelow returns the mean length of stays below the lower threshold.
+func (s ZoneStats) MeanBelow() DType {
+	return zoneRatio(s.Below, s.BelowSpells)
+}
+
+// TimeInZone counts consecutive bars the oscillator has been above upper and below lower thresholds,
+// e.g. for how many bars RSI has been over 70. Counts are zero outside the zones.
+// NaN values of the oscillator are NaN in the counts and interrupt the stays.
+func TimeInZone(osc series.Data, lower, upper float64) (above, below series.Data, stats ZoneStats) {
+	var (
+		values = osc.Values()
+		lo     = DType(lower)
+		hi     = DType(upper)
+
+		up, down int
+	)
+
+	above = osc.Clone()
+	below = osc.Clone()
+
+	a := above.Values()
+	b := below.Values()
+
+	for i, v := range values {
+		if series.IsNA(v) {
+			up, down = 0, 0
+			a[i], b[i] = math.NaN(), math.NaN()
+			continue
+		}
+
+		stats.Bars++
+
+		if v > hi {
+			if up == 0 {
+				stats.AboveSpells++
+			}
+			up++
+			stats.Above++
+			if up > stats.LongestAbove {
+				stats.LongestAbove = up
+			}
+		} else {
+			up = 0
+		}
+
+		if v < lo {
+			if down == 0 {
+				stats.BelowSpells++
+			}
+			down++
+			stats.Below++
+			if down > stats.LongestBelow {
+				stats.LongestBelow = down
+			}
+		} else {
+			down = 0
+		}
+
+		a[i], b[i] = DType(up), DType(down)
+	}
+
+	return above, below, stats
+}
+
+func zoneRatio(n, d int) DType {
+	if d == 0 {
+		return math.NaN()
+	}
+	return DType(n) / DType(d)
+}